@@ -0,0 +1,196 @@
+package ckoanf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// cacheEntry is the on-disk format of the cache of a `Cached` source.
+type cacheEntry struct {
+	SavedAt time.Time              `json:"saved_at"`
+	Values  map[string]interface{} `json:"values"`
+	// The profiles the source defined, see `WithProfile`.
+	Profiles []string `json:"profiles,omitempty"`
+	// The override blocks the source defined, see `WithAttributes`.
+	Overrides []cachedOverride `json:"overrides,omitempty"`
+}
+
+// cachedOverride is an override block in the cache file.
+type cachedOverride struct {
+	Location string                 `json:"location"`
+	Index    int                    `json:"index"`
+	When     map[string][]string    `json:"when"`
+	Values   map[string]interface{} `json:"values"`
+}
+
+// Cached wraps a source and keeps a last-known-good copy of what it loaded in the file at the given path.
+//
+// Every time the source loads successfully, the values it loaded are written to the cache file.
+// When the source fails to load, the values are loaded from the cache file instead, unless it is older
+// than maxAge (0 means any age is fine). The load report lists such sources as `SourceStatusCached`,
+// with the error of the source. If there is no usable cache, the error of the source is returned.
+//
+// The cache file is only readable by the current user, as the config may contain secrets.
+func Cached[C ConfigModel](src SourceFunc[C], path string, maxAge time.Duration) SourceFunc[C] {
+	return func(mgr *Config[C]) (Source, error) {
+		if src == nil {
+			return Source{}, fmt.Errorf("source cannot be nil")
+		}
+		if path == "" {
+			return Source{}, fmt.Errorf("cache path cannot be empty")
+		}
+		if maxAge < 0 {
+			return Source{}, fmt.Errorf("max cache age cannot be negative")
+		}
+
+		innerSrc, err := src(mgr)
+		if err != nil {
+			return Source{}, err
+		}
+
+		src := innerSrc
+		src.Load = func(ctx context.Context, k *koanf.Koanf) error {
+			state := sourceStateFrom(ctx)
+			var profiles, blocks int
+			if state != nil {
+				profiles, blocks = len(state.profiles), len(state.blocks)
+			}
+
+			layer, err := loadLayer(ctx, innerSrc, k.Delim())
+			if err == nil {
+				if state != nil && state.skipped != nil {
+					return nil
+				}
+				entry := cacheEntry{Values: layer.Raw()}
+				if state != nil {
+					entry.Profiles = state.profiles[profiles:]
+					for _, block := range state.blocks[blocks:] {
+						entry.Overrides = append(entry.Overrides, cachedOverride{
+							Location: block.report.Location, Index: block.report.Index,
+							When: block.report.When, Values: block.values,
+						})
+					}
+				}
+				if writeErr := writeCache(path, entry); writeErr != nil {
+					mgr.log(ctx, slog.LevelWarn, "failed to write config cache",
+						slog.String("path", path), slog.String("error", writeErr.Error()))
+				}
+				return k.Merge(layer)
+			}
+
+			entry, cacheErr := readCache(path, maxAge)
+			if cacheErr != nil {
+				if errors.Is(cacheErr, fs.ErrNotExist) {
+					return err
+				}
+				return fmt.Errorf("%w (no fallback to cache: %w)", err, cacheErr)
+			}
+
+			if loadErr := k.Load(mapProvider(entry.Values), nil); loadErr != nil {
+				return fmt.Errorf("%w (failed to load cache: %w)", err, loadErr)
+			}
+			flat, _ := maps.Flatten(entry.Values, nil, k.Delim())
+			recordOrigin(ctx, path, flat, nil)
+			markProfiles(ctx, entry.Profiles...)
+			if state != nil {
+				for _, override := range entry.Overrides {
+					state.blocks = append(state.blocks, overrideBlock{
+						report: OverrideReport{Location: override.Location, Index: override.Index, When: override.When},
+						values: override.Values,
+					})
+				}
+			}
+			markFallback(ctx, err)
+			return nil
+		}
+		return src, nil
+	}
+}
+
+// writeCache atomically replaces the cache file with the given entry.
+func writeCache(path string, entry cacheEntry) error {
+	entry.SavedAt = time.Now()
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// readCache reads the cache file, and fails if it is older than maxAge.
+func readCache(path string, maxAge time.Duration) (cacheEntry, error) {
+	var entry cacheEntry
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return entry, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&entry); err != nil {
+		return entry, fmt.Errorf("invalid cache file %s: %w", path, err)
+	}
+	entry.Values, _ = restoreNumbers(entry.Values).(map[string]interface{})
+	for i := range entry.Overrides {
+		entry.Overrides[i].Values, _ = restoreNumbers(entry.Overrides[i].Values).(map[string]interface{})
+	}
+
+	if age := time.Since(entry.SavedAt); maxAge > 0 && age > maxAge {
+		return entry, fmt.Errorf("cache file %s is %s old, which is more than the max age of %s",
+			path, age.Round(time.Second), maxAge)
+	}
+	return entry, nil
+}
+
+// restoreNumbers replaces the numbers decoded from the cache file with integers (as int64) where they are
+// integers and float64 otherwise, so that large integers keep their precision.
+func restoreNumbers(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]interface{}:
+		for key, elem := range v {
+			v[key] = restoreNumbers(elem)
+		}
+		return v
+	case []interface{}:
+		for i, elem := range v {
+			v[i] = restoreNumbers(elem)
+		}
+		return v
+	default:
+		return value
+	}
+}
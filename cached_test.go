@@ -0,0 +1,174 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCached(t *testing.T) {
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("key = 'from_remote'\n[nested]\nfoo = 'bar'"))
+	}))
+	defer server.Close()
+
+	remote := Remote[*TestModel](server.URL+"/config.toml", RemoteOptions{})
+
+	t.Run("Falls back to cache", func(t *testing.T) {
+		down.Store(false)
+		path := filepath.Join(t.TempDir(), "cache", "config.json")
+
+		cfg, err := Init(&TestModel{}, WithSource(Cached(remote, path, time.Hour)))
+		require.NoError(t, err)
+		assert.Equal(t, SourceStatusLoaded, cfg.Report().Sources[0].Status)
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		// A new process, started while the remote is down
+		down.Store(true)
+		cfg, err = Init(&TestModel{}, WithSource(Cached(remote, path, time.Hour)))
+		require.NoError(t, err)
+		assert.Equal(t, "from_remote", cfg.Model().Key)
+		assert.Equal(t, "bar", cfg.Model().Nested.Foo)
+
+		report := cfg.Report().Sources[0]
+		assert.Equal(t, SourceStatusCached, report.Status)
+		assert.Contains(t, report.Err.Error(), "503 Service Unavailable")
+		assert.Equal(t, 2, report.Keys)
+
+		exp, ok := cfg.Explain("key")
+		require.True(t, ok)
+		assert.Equal(t, path, exp.Origins[0].Location)
+		assert.Equal(t, SourceTypeRemote, exp.Origins[0].Type)
+	})
+
+	t.Run("Keeps numbers", func(t *testing.T) {
+		var fail atomic.Bool
+		numbers := func(mgr *Config[*TestModel]) (Source, error) {
+			return Source{Type: SourceTypeDefault, Load: func(ctx context.Context, k *koanf.Koanf) error {
+				if fail.Load() {
+					return errors.New("source is down")
+				}
+				return k.Load(mapProvider(map[string]interface{}{
+					"key": "numbers", "big": int64(1<<62 + 1), "float": 1.5, "list": []interface{}{int64(1), int64(2)},
+				}), nil)
+			}}, nil
+		}
+		path := filepath.Join(t.TempDir(), "config.json")
+		_, err := Init(&TestModel{}, WithSource(Cached(numbers, path, 0)))
+		require.NoError(t, err)
+
+		fail.Store(true)
+		cfg, err := Init(&TestModel{}, WithSource(Cached(numbers, path, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, SourceStatusCached, cfg.Report().Sources[0].Status)
+		assert.Equal(t, int64(1<<62+1), cfg.K.Get("big"))
+		assert.Equal(t, 1.5, cfg.K.Get("float"))
+		assert.Equal(t, []interface{}{int64(1), int64(2)}, cfg.K.Get("list"))
+	})
+
+	t.Run("Keeps profiles", func(t *testing.T) {
+		var down atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if down.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte("key = 'base'\n[profile.prod]\nkey = 'prod'"))
+		}))
+		defer server.Close()
+
+		remote := Remote[*TestModel](server.URL+"/config.toml", RemoteOptions{})
+		path := filepath.Join(t.TempDir(), "config.json")
+		_, err := Init(&TestModel{}, WithProfile[*TestModel]("prod"), WithSource(Cached(remote, path, 0)))
+		require.NoError(t, err)
+
+		down.Store(true)
+		cfg, err := Init(&TestModel{}, WithProfile[*TestModel]("prod"), WithSource(Cached(remote, path, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, SourceStatusCached, cfg.Report().Sources[0].Status)
+		assert.Equal(t, "prod", cfg.Model().Key)
+	})
+
+	t.Run("Keeps override blocks", func(t *testing.T) {
+		var down atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if down.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write([]byte("key = 'base'\n[[override]]\nwhen = { region = 'eu-*' }\nabc = 'eu1'"))
+		}))
+		defer server.Close()
+
+		remote := Remote[*TestModel](server.URL+"/config.toml", RemoteOptions{})
+		path := filepath.Join(t.TempDir(), "config.json")
+		attrs := WithAttributes[*TestModel](map[string]string{"region": "eu-west-1"})
+		cfg, err := Init(&TestModel{}, attrs, WithSource(Cached(remote, path, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, "eu1", cfg.Model().ABC)
+
+		down.Store(true)
+		cfg, err = Init(&TestModel{}, attrs, WithSource(Cached(remote, path, 0)))
+		require.NoError(t, err)
+		assert.Equal(t, SourceStatusCached, cfg.Report().Sources[0].Status)
+		assert.Equal(t, "eu1", cfg.Model().ABC)
+		require.Len(t, cfg.Report().Overrides, 1)
+		assert.True(t, cfg.Report().Overrides[0].Applied)
+		assert.Equal(t, server.URL+"/config.toml", cfg.Report().Overrides[0].Location)
+	})
+
+	t.Run("Refuses old cache", func(t *testing.T) {
+		down.Store(false)
+		path := filepath.Join(t.TempDir(), "config.json")
+		_, err := Init(&TestModel{}, WithSource(Cached(remote, path, time.Hour)))
+		require.NoError(t, err)
+
+		down.Store(true)
+		_, err = Init(&TestModel{}, WithSource(Cached(remote, path, time.Nanosecond)))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "503 Service Unavailable")
+		assert.Contains(t, err.Error(), "which is more than the max age of 1ns")
+	})
+
+	t.Run("No cache", func(t *testing.T) {
+		down.Store(true)
+		path := filepath.Join(t.TempDir(), "config.json")
+		cfg, err := New(&TestModel{}, WithSource(Cached(remote, path, 0)))
+		require.NoError(t, err)
+
+		err = cfg.Load(context.Background())
+		var loadErr *SourceLoadError
+		require.ErrorAs(t, err, &loadErr)
+		assert.NotContains(t, err.Error(), "cache")
+		assert.Equal(t, SourceStatusFailed, cfg.Report().Sources[0].Status)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, src := range []SourceFunc[*TestModel]{
+			Cached[*TestModel](nil, "cache.json", 0),
+			Cached(remote, "", 0),
+			Cached(remote, "cache.json", -time.Second),
+		} {
+			_, err := New(&TestModel{}, WithSource(src))
+			assert.Error(t, err)
+		}
+	})
+}
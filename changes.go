@@ -0,0 +1,171 @@
+package ckoanf
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ChangeFunc is called after the config changed, with snapshots of the model before and after the
+// change and the sorted key paths (for example `nested.foo`) of the values that changed.
+// Like with `Snapshot`, the models must not be modified.
+type ChangeFunc[C ConfigModel] func(old, new C, changed []string)
+
+type subscription[C ConfigModel] struct {
+	id uint64
+	// Only notify for changes at or below this key path, empty means all changes.
+	key string
+	fn  ChangeFunc[C]
+}
+
+// OnChange registers a function that is called whenever the config changes, either because it
+// was (re)loaded or because `Set` was called. It is not called if no values changed.
+//
+// Subscribers are called one change at a time, in the order the changes were made. Changes made from
+// within a subscriber are delivered after it returns.
+//
+// The returned function removes the subscription.
+func (mgr *Config[C]) OnChange(fn ChangeFunc[C]) func() {
+	return mgr.subscribe("", fn)
+}
+
+// OnKeyChange registers a function that is called whenever a value at or below the given key path
+// changes, for example `db` matches changes to `db.pool_size`.
+// Only the changed key paths under the given key are passed to the function.
+//
+// The returned function removes the subscription.
+func (mgr *Config[C]) OnKeyChange(key string, fn ChangeFunc[C]) func() {
+	return mgr.subscribe(key, fn)
+}
+
+func (mgr *Config[C]) subscribe(key string, fn ChangeFunc[C]) func() {
+	mgr.subsMu.Lock()
+	defer mgr.subsMu.Unlock()
+
+	mgr.nextSubID++
+	id := mgr.nextSubID
+	mgr.subs = append(mgr.subs, subscription[C]{id: id, key: key, fn: fn})
+
+	return func() {
+		mgr.subsMu.Lock()
+		defer mgr.subsMu.Unlock()
+
+		for i, sub := range mgr.subs {
+			if sub.id == id {
+				mgr.subs = append(mgr.subs[:i:i], mgr.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// snapshotChange is a change of the config that the subscribers have to be notified of.
+type snapshotChange[C ConfigModel] struct {
+	old, updated *snapshot[C]
+}
+
+// queueChange queues a change for deliverChanges. The caller must hold the lock, so that changes
+// are queued in the order they were made.
+func (mgr *Config[C]) queueChange(old, updated *snapshot[C]) {
+	if old == updated {
+		return
+	}
+	mgr.changesMu.Lock()
+	mgr.changes = append(mgr.changes, snapshotChange[C]{old: old, updated: updated})
+	mgr.changesMu.Unlock()
+}
+
+// deliverChanges notifies the subscribers of the queued changes, in the order they were made.
+// If another goroutine is already delivering changes it delivers the new ones as well, so that subscribers
+// are never called concurrently or out of order, and can change the config themselves.
+func (mgr *Config[C]) deliverChanges() {
+	mgr.changesMu.Lock()
+	if mgr.delivering {
+		mgr.changesMu.Unlock()
+		return
+	}
+	mgr.delivering = true
+	mgr.changesMu.Unlock()
+
+	done := false
+	defer func() {
+		// A subscriber panicked, let the next change deliver the rest.
+		if !done {
+			mgr.changesMu.Lock()
+			mgr.delivering = false
+			mgr.changesMu.Unlock()
+		}
+	}()
+
+	for {
+		mgr.changesMu.Lock()
+		if len(mgr.changes) == 0 {
+			mgr.delivering, done = false, true
+			mgr.changesMu.Unlock()
+			return
+		}
+		change := mgr.changes[0]
+		mgr.changes = mgr.changes[1:]
+		mgr.changesMu.Unlock()
+
+		mgr.notifySnapshots(change.old, change.updated)
+	}
+}
+
+// notifySnapshots notifies the subscribers of the changes between two snapshots.
+func (mgr *Config[C]) notifySnapshots(old, updated *snapshot[C]) {
+	if old == updated {
+		return
+	}
+	mgr.notify(old.model, updated.model, diffKeys(old.k.All(), updated.k.All()))
+}
+
+// notify calls the subscribers interested in the changed keys, in order of registration.
+func (mgr *Config[C]) notify(old, updated C, changed []string) {
+	if len(changed) == 0 {
+		return
+	}
+
+	mgr.subsMu.Lock()
+	subs := append([]subscription[C](nil), mgr.subs...)
+	mgr.subsMu.Unlock()
+
+	for _, sub := range subs {
+		keys := changed
+		if sub.key != "" {
+			keys = keysUnder(changed, sub.key)
+		}
+		if len(keys) > 0 {
+			sub.fn(old, updated, keys)
+		}
+	}
+}
+
+// diffKeys returns the sorted keys of two flattened config maps whose values differ.
+func diffKeys(before, after map[string]interface{}) []string {
+	var changed []string
+	for key, value := range before {
+		if afterValue, ok := after[key]; !ok || !reflect.DeepEqual(value, afterValue) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+// keysUnder returns the keys that are equal to or nested below the given key path.
+func keysUnder(keys []string, key string) []string {
+	var matched []string
+	for _, k := range keys {
+		if k == key || strings.HasPrefix(k, key+defaultDelimiter) {
+			matched = append(matched, k)
+		}
+	}
+	return matched
+}
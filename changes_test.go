@@ -0,0 +1,141 @@
+package ckoanf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnChange(t *testing.T) {
+	t.Run("Set", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML)))
+		require.NoError(t, err)
+
+		var calls int
+		var oldKey, newKey string
+		var changed []string
+		unsubscribe := cfg.OnChange(func(old, new *TestModel, keys []string) {
+			calls++
+			oldKey, newKey, changed = old.Key, new.Key, keys
+		})
+
+		require.NoError(t, cfg.Set("key", "new_value"))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "value", oldKey)
+		assert.Equal(t, "new_value", newKey)
+		assert.Equal(t, []string{"key"}, changed)
+
+		// Setting the same value is not a change
+		require.NoError(t, cfg.Set("key", "new_value"))
+		assert.Equal(t, 1, calls)
+
+		unsubscribe()
+		require.NoError(t, cfg.Set("key", "other_value"))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("OnKeyChange", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML)))
+		require.NoError(t, err)
+
+		var nestedChanges [][]string
+		cfg.OnKeyChange("nested", func(old, new *TestModel, keys []string) {
+			nestedChanges = append(nestedChanges, keys)
+		})
+		var fooChanges int
+		cfg.OnKeyChange("nested.foo", func(old, new *TestModel, keys []string) {
+			fooChanges++
+		})
+
+		require.NoError(t, cfg.Set("key", "new_value"))
+		assert.Empty(t, nestedChanges)
+
+		require.NoError(t, cfg.Set("nested.foo", "baz"))
+		require.NoError(t, cfg.Set("nested.bar", "qux"))
+		assert.Equal(t, [][]string{{"nested.foo"}, {"nested.bar"}}, nestedChanges)
+		assert.Equal(t, 1, fooChanges)
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(path, []byte("key = 'old'\nextra = 'x'"), 0o600))
+
+		cfg, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](path)))
+		require.NoError(t, err)
+
+		var changed []string
+		var newKey string
+		cfg.OnChange(func(old, new *TestModel, keys []string) {
+			changed, newKey = keys, new.Key
+		})
+
+		require.NoError(t, os.WriteFile(path, []byte("key = 'new'\nabc = 'abc'"), 0o600))
+		require.NoError(t, cfg.Load(context.Background()))
+		assert.Equal(t, []string{"abc", "extra", "key"}, changed)
+		assert.Equal(t, "new", newKey)
+	})
+
+	t.Run("Concurrent changes in order", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML)))
+		require.NoError(t, err)
+
+		type change struct {
+			old, new string
+			keys     []string
+		}
+		var changes []change
+		cfg.OnChange(func(old, new *TestModel, keys []string) {
+			changes = append(changes, change{old: old.Key, new: new.Key, keys: keys})
+		})
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				assert.NoError(t, cfg.Set("key", fmt.Sprintf("value_%d", i)))
+			}(i)
+		}
+		wg.Wait()
+
+		require.Len(t, changes, 20)
+		assert.Equal(t, "value", changes[0].old)
+		for i, c := range changes {
+			assert.Equal(t, []string{"key"}, c.keys)
+			if i > 0 {
+				assert.Equal(t, changes[i-1].new, c.old)
+			}
+		}
+		assert.Equal(t, cfg.Snapshot().Key, changes[len(changes)-1].new)
+	})
+
+	t.Run("Change from subscriber", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML)))
+		require.NoError(t, err)
+
+		var changed [][]string
+		cfg.OnChange(func(old, new *TestModel, keys []string) {
+			changed = append(changed, keys)
+			if new.ABC != "sub" {
+				assert.NoError(t, cfg.Set("abc", "sub"))
+			}
+		})
+
+		require.NoError(t, cfg.Set("key", "new_value"))
+		assert.Equal(t, [][]string{{"key"}, {"abc"}}, changed)
+	})
+}
+
+func TestDiffKeys(t *testing.T) {
+	before := map[string]interface{}{"a": 1, "b": []interface{}{1, 2}, "c": "same", "d": "removed"}
+	after := map[string]interface{}{"a": 2, "b": []interface{}{1, 2}, "c": "same", "e": "added"}
+
+	assert.Equal(t, []string{"a", "d", "e"}, diffKeys(before, after))
+	assert.Empty(t, diffKeys(before, before))
+}
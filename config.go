@@ -3,7 +3,12 @@ package ckoanf
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/knadh/koanf/v2"
@@ -23,6 +28,17 @@ type Config[C ConfigModel] struct {
 	K     *koanf.Koanf
 	model C
 
+	// A copy of the model as it was passed to `New`, every load unmarshals into a copy of it.
+	initial C
+
+	// Serializes loads, reloads and sets.
+	mu sync.Mutex
+
+	// The config of the last successful load or set, readers never block on it.
+	current atomic.Pointer[snapshot[C]]
+	// The report of the last load.
+	report atomic.Pointer[LoadReport]
+
 	// The sources to load the config from.
 	// The order of the sources is important, as the config will be loaded in the same order.
 	// Later sources will override values from earlier sources.
@@ -31,7 +47,33 @@ type Config[C ConfigModel] struct {
 	// Defaults to true
 	validationEnabled bool
 	strictMerge       bool
+	bestEffort        bool
+	interpolation     bool
+	profilesEnabled   bool
+	profile           string
+	overrides         bool
+	attributes        map[string]string
 	loadTimeout       time.Duration
+
+	reloadDebounce     time.Duration
+	reloadErrorHandler func(error)
+
+	logger     *slog.Logger
+	secretKeys []string
+	// Keys whose values were resolved by a resolver, they are secret from then on.
+	resolvedSecrets atomic.Pointer[[]string]
+
+	// Resolvers for references in config values by scheme, see `WithResolver`.
+	resolvers map[string]Resolver
+
+	subsMu    sync.Mutex
+	subs      []subscription[C]
+	nextSubID uint64
+
+	// Changes that subscribers still have to be notified of, in the order they were made.
+	changesMu  sync.Mutex
+	changes    []snapshotChange[C]
+	delivering bool
 }
 
 // New creates a new config manager.
@@ -41,7 +83,13 @@ func New[C ConfigModel](c C, opts ...Option[C]) (*Config[C], error) {
 		validationEnabled: true,
 		strictMerge:       false,
 		model:             c,
+		initial:           cloneModel(c),
 		loadTimeout:       time.Second * 10,
+		reloadDebounce:    defaultReloadDebounce,
+	}
+
+	if t := reflect.TypeOf(c); t != nil {
+		mgr.secretKeys = modelSecretKeys(t, "", make(map[reflect.Type]bool))
 	}
 
 	for i, opt := range opts {
@@ -57,24 +105,41 @@ func New[C ConfigModel](c C, opts ...Option[C]) (*Config[C], error) {
 		}
 	}
 
-	mgr.K = koanf.NewWithConf(koanf.Conf{
+	mgr.K = mgr.newKoanf()
+	mgr.current.Store(&snapshot[C]{k: mgr.K, model: cloneModel(mgr.initial)})
+
+	return mgr, nil
+}
+
+// newKoanf returns an empty koanf instance with the config manager's settings.
+func (mgr *Config[C]) newKoanf() *koanf.Koanf {
+	return koanf.NewWithConf(koanf.Conf{
 		Delim:       defaultDelimiter,
 		StrictMerge: mgr.strictMerge,
 	})
-
-	return mgr, nil
 }
 
 // Validate the config model by calling its `Validate` method.
+// Failures are returned as a `*ValidationError`.
 func (mgr *Config[C]) Validate() error {
 	if err := mgr.model.Validate(); err != nil {
-		return fmt.Errorf("failed to validate config model: %w", err)
+		snap := mgr.current.Load()
+		return mgr.newValidationError(mgr.model, snap.k, snap.origins, err)
 	}
 	return nil
 }
 
 // Load the config from the given sources.
 // Optionally takes a context to use for the load operation.
+//
+// Failures are returned as a `*SourceLoadError`, `*UnmarshalError` or `*ValidationError`.
+// Loading stops at the first failure, unless best-effort mode is enabled with `WithBestEffort`.
+//
+// The sources are merged into a fresh koanf instance and unmarshalled into a fresh copy of the model.
+// Only if that succeeds (and the model passes validation) are both swapped in, on any failure the
+// previous config stays in place.
+//
+// Note that values changed with `Set` are discarded when the config is loaded again.
 func (mgr *Config[C]) Load(ctxs ...context.Context) error {
 	baseContext := context.Background()
 	if len(ctxs) > 1 {
@@ -87,25 +152,235 @@ func (mgr *Config[C]) Load(ctxs ...context.Context) error {
 	ctx, cancel := context.WithTimeout(baseContext, mgr.loadTimeout)
 	defer cancel()
 
-	for i, source := range mgr.sources {
-		if err := source.Load(ctx, mgr.K); err != nil {
-			return fmt.Errorf("failed to load config from provider %d (type=%s): %w", i, source.Type, err)
+	mgr.mu.Lock()
+	old := mgr.current.Load()
+	report := &LoadReport{Start: time.Now()}
+	err := mgr.load(ctx, report)
+	report.Duration, report.Err = time.Since(report.Start), err
+	mgr.report.Store(report)
+	if err == nil {
+		mgr.queueChange(old, mgr.current.Load())
+	}
+	mgr.mu.Unlock()
+
+	if err != nil {
+		mgr.logLoadError(ctx, err)
+		return err
+	}
+
+	mgr.log(ctx, slog.LevelInfo, "loaded config", slog.Duration("duration", report.Duration))
+	mgr.deliverChanges()
+	return nil
+}
+
+// load builds the new config and swaps it in, the caller must hold the lock.
+func (mgr *Config[C]) load(ctx context.Context, report *LoadReport) error {
+	k := mgr.newKoanf()
+	origins, err := mgr.loadSources(ctx, k, report)
+	if mgr.interpolation && (err == nil || mgr.bestEffort) {
+		err = errors.Join(err, mgr.interpolate(ctx, k))
+	}
+	if err != nil {
+		if mgr.bestEffort {
+			// Still unmarshal and validate what could be loaded, to report all problems at once.
+			if _, buildErr := mgr.build(k, origins, true); buildErr != nil {
+				return errors.Join(err, buildErr)
+			}
 		}
+		return err
 	}
 
-	if err := mgr.K.Unmarshal("", mgr.model); err != nil {
-		return fmt.Errorf("failed to unmarshal config: %w", err)
+	return mgr.commit(k, origins, true)
+}
+
+// build unmarshals the given koanf instance into a fresh copy of the model and optionally validates it.
+//
+//nolint:ireturn,nolintlint // Generic return, false positive by the linter
+func (mgr *Config[C]) build(k *koanf.Koanf, origins []Origin, validate bool) (C, error) {
+	model := cloneModel(mgr.initial)
+	if err := unmarshal(k, model); err != nil {
+		return model, &UnmarshalError{Err: err}
 	}
 
-	if mgr.validationEnabled {
-		if err := mgr.Validate(); err != nil {
-			return err
+	if validate && mgr.validationEnabled {
+		if err := model.Validate(); err != nil {
+			return model, mgr.newValidationError(model, k, origins, err)
 		}
 	}
+	return model, nil
+}
+
+// commit builds the model from the given koanf instance and then swaps both in.
+// On failure the current config is left untouched. The caller must hold the lock.
+func (mgr *Config[C]) commit(k *koanf.Koanf, origins []Origin, validate bool) error {
+	model, err := mgr.build(k, origins, validate)
+	if err != nil {
+		return err
+	}
 
+	mgr.K = k
+	mgr.model = assignModel(mgr.model, cloneModel(model))
+	mgr.current.Store(&snapshot[C]{k: k, model: model, origins: origins})
 	return nil
 }
 
+// loadSources loads every source into its own koanf instance and merges those in order into
+// the given koanf instance. It returns the origins of all loaded values in load order.
+//
+// Parallel sources all start loading up front, but are merged in order like the other sources.
+// If there are deferred sources, the other sources are loaded first to bootstrap them.
+// The override blocks of the sources are applied after all sources are merged.
+// In best-effort mode failing sources are skipped, and all their errors are joined together.
+// What happened to each source is added to the report.
+func (mgr *Config[C]) loadSources(ctx context.Context, k *koanf.Koanf, report *LoadReport) ([]Origin, error) {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	// Stops the parallel sources that are still loading when returning early.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fetches := make([]chan *fetchedSource, len(mgr.sources))
+	startParallel := func(ctx context.Context, deferred bool) {
+		for i, source := range mgr.sources {
+			if !source.Parallel || source.deferred != deferred {
+				continue
+			}
+			fetches[i] = make(chan *fetchedSource, 1)
+			wg.Add(1)
+			go func(i int, source Source) {
+				defer wg.Done()
+				fetches[i] <- mgr.fetchSource(ctx, i, source, nil)
+			}(i, source)
+		}
+	}
+
+	// Sources that were already loaded to bootstrap the deferred sources.
+	prefetched := make([]*fetchedSource, len(mgr.sources))
+	startParallel(ctx, false)
+	if mgr.hasDeferredSources() {
+		ctx = withBootstrap(ctx, mgr.bootstrap(ctx, fetches, prefetched))
+		startParallel(ctx, true)
+	}
+
+	var (
+		origins   []Origin
+		errs      []error
+		profiles  []string
+		overrides []overrideBlock
+	)
+	for i, source := range mgr.sources {
+		fetched := prefetched[i]
+		switch {
+		case fetched != nil:
+		case fetches[i] != nil:
+			fetched = <-fetches[i]
+		default:
+			fetched = mgr.fetchSource(ctx, i, source, k)
+		}
+		profiles = append(profiles, fetched.state.profiles...)
+
+		srcOrigins, srcReport := mgr.mergeSource(ctx, i, source, k, fetched)
+		report.Sources = append(report.Sources, srcReport)
+		mgr.logSourceReport(ctx, srcReport)
+
+		if srcReport.Status == SourceStatusFailed {
+			loadErr := &SourceLoadError{Index: i, Type: srcReport.Type, Location: srcReport.Location, Err: srcReport.Err}
+			if !mgr.bestEffort {
+				return nil, loadErr
+			}
+			errs = append(errs, loadErr)
+			continue
+		}
+		origins = append(origins, srcOrigins...)
+		overrides = append(overrides, fetched.overrides...)
+	}
+
+	if err := mgr.checkProfile(profiles); err != nil {
+		if !mgr.bestEffort {
+			return nil, err
+		}
+		errs = append(errs, err)
+	}
+
+	overrideOrigins, overrideReports, err := mgr.applyOverrides(ctx, k, overrides)
+	report.Overrides = overrideReports
+	if err != nil {
+		if !mgr.bestEffort {
+			return nil, err
+		}
+		errs = append(errs, err)
+	}
+	origins = append(origins, overrideOrigins...)
+	return origins, errors.Join(errs...)
+}
+
+// fetchedSource is the result of loading a single source into its own koanf instance.
+type fetchedSource struct {
+	state     *sourceState
+	layer     *koanf.Koanf
+	overrides []overrideBlock
+	err       error
+	duration  time.Duration
+}
+
+// fetchSource loads a single source into its own koanf instance, merged is the config
+// merged from the sources before it, nil for parallel sources.
+func (mgr *Config[C]) fetchSource(ctx context.Context, i int, source Source, merged *koanf.Koanf) *fetchedSource {
+	mgr.log(ctx, slog.LevelDebug, "loading config source", sourceAttrs(i, source)...)
+
+	state := &sourceState{
+		merged: merged, profilesEnabled: mgr.profilesEnabled, profile: mgr.profile, overrides: mgr.overrides,
+	}
+	layer := mgr.newKoanf()
+	start := time.Now()
+	err := source.Load(withSourceState(ctx, state), layer)
+	duration := time.Since(start)
+
+	var overrides []overrideBlock
+	if err == nil && state.skipped == nil {
+		overrides = state.overrideBlocks(i, source)
+	}
+	return &fetchedSource{state: state, layer: layer, overrides: overrides, err: err, duration: duration}
+}
+
+// mergeSource merges a loaded source into the given koanf instance, unless it failed or was skipped.
+func (mgr *Config[C]) mergeSource(
+	ctx context.Context, i int, source Source, k *koanf.Koanf, fetched *fetchedSource,
+) ([]Origin, SourceReport) {
+	state, layer, err := fetched.state, fetched.layer, fetched.err
+	if state.resolved != nil {
+		source.Type, source.Location = state.resolved.Type, state.resolved.Location
+	}
+
+	var origins []Origin
+	if err == nil && state.skipped == nil {
+		origins = state.sourceOrigins(i, source, layer)
+		mgr.logOverrides(ctx, k, origins)
+		if mergeErr := k.Merge(layer); mergeErr != nil {
+			err = fmt.Errorf("failed to merge config: %w", mergeErr)
+		}
+	}
+
+	report := SourceReport{
+		Index: i, Type: source.Type, Location: source.Location,
+		Status: SourceStatusLoaded, Duration: fetched.duration,
+	}
+	switch {
+	case err != nil:
+		report.Status, report.Err = SourceStatusFailed, err
+		return nil, report
+	case state.skipped != nil:
+		report.Status, report.Err = SourceStatusSkipped, state.skipped
+		return nil, report
+	case state.fallback != nil:
+		report.Status, report.Err, report.Keys = SourceStatusCached, state.fallback, len(layer.All())
+		return origins, report
+	default:
+		report.Keys = len(layer.All())
+		return origins, report
+	}
+}
+
 // Init creates a new config manager and loads the config from the given sources.
 // This is equivalent to calling `New` and `Load` in sequence.
 func Init[C ConfigModel](c C, opts ...Option[C]) (*Config[C], error) {
@@ -123,6 +398,9 @@ func Init[C ConfigModel](c C, opts ...Option[C]) (*Config[C], error) {
 
 // Model returns the underlying model of the config manager.
 //
+// The model is updated in place by `Load`, `Set` and reloads triggered by `Watch`, so reading it
+// concurrently with those is a data race. Use `Snapshot` or `View` for concurrent access.
+//
 //nolint:ireturn,nolintlint // Generic return, false positive by the linter
 func (mgr *Config[C]) Model() C {
 	return mgr.model
@@ -131,14 +409,44 @@ func (mgr *Config[C]) Model() C {
 // Set changes a value in the config by path key.
 // Note that this requires unmarshaling and is fairly expensive.
 func (mgr *Config[C]) Set(key string, value interface{}) error {
-	err := mgr.K.Set(key, value)
+	mgr.mu.Lock()
+	old := mgr.current.Load()
+	err := mgr.set(key, value)
+	if err == nil {
+		mgr.queueChange(old, mgr.current.Load())
+	}
+	mgr.mu.Unlock()
+
+	if err != nil {
+		mgr.log(context.Background(), slog.LevelWarn, "failed to set config value", slog.String("key", key),
+			slog.String("error", err.Error()))
+		return err
+	}
+
+	mgr.log(context.Background(), slog.LevelInfo, "set config value", slog.String("key", key),
+		slog.Any("value", mgr.redactTree(key, value)))
+
+	mgr.deliverChanges()
+	return nil
+}
+
+// set changes a value on a copy of the koanf instance and swaps it in, the caller must hold the lock.
+func (mgr *Config[C]) set(key string, value interface{}) error {
+	k := mgr.K.Copy()
+	err := k.Set(key, value)
 	if err != nil {
 		return fmt.Errorf("ckoanf failed to set value: %w", err)
 	}
 
-	err = mgr.K.Unmarshal("", mgr.model)
+	prev := mgr.current.Load()
+	origins := append([]Origin(nil), prev.origins...)
+	for _, leaf := range keysUnder(k.Keys(), key) {
+		origins = append(origins, Origin{Source: -1, Type: SourceTypeSet, Key: leaf, Value: k.Get(leaf)})
+	}
+
+	err = mgr.commit(k, origins, false)
 	if err != nil {
-		return fmt.Errorf("ckoanf failed to unmarshal config: %w", err)
+		return fmt.Errorf("ckoanf %w", err)
 	}
 	return nil
 }
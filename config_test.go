@@ -3,6 +3,7 @@ package ckoanf
 import (
 	"context"
 	"errors"
+	"os"
 	"testing"
 	"time"
 
@@ -158,3 +159,94 @@ func TestSet(t *testing.T) {
 	model = cfg.Model()
 	assert.Equal(t, "new_foo", model.Nested.Foo)
 }
+
+func TestTransactionalLoad(t *testing.T) {
+	path := t.TempDir() + "/config.toml"
+	assert.NoError(t, os.WriteFile(path, []byte("key = 'good'\n[nested]\nfoo = 'bar'"), 0o600))
+
+	model := &TestModel{ABC: "xyz"}
+	cfg, err := Init(model,
+		WithSource(
+			LocalFile[*TestModel](path),
+			Env[*TestModel]("TRANSACTIONAL_LOAD_TEST__"),
+		),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "good", model.Key)
+	assert.Equal(t, "xyz", model.ABC) // Initial values are kept
+
+	assertUnchanged := func() {
+		t.Helper()
+		assert.Equal(t, "good", cfg.Model().Key)
+		assert.Equal(t, "bar", cfg.Model().Nested.Foo)
+		assert.Equal(t, "xyz", cfg.Model().ABC)
+		assert.Equal(t, "good", cfg.K.String("key"))
+		assert.Equal(t, "bar", cfg.K.String("nested.foo"))
+		assert.Same(t, model, cfg.Model())
+	}
+
+	// Validation fails, ABC must be length 3
+	t.Setenv("TRANSACTIONAL_LOAD_TEST__ABC", "abcd")
+	assert.NoError(t, os.WriteFile(path, []byte("key = 'changed'\n[nested]\nfoo = 'baz'"), 0o600))
+	assert.Error(t, cfg.Load())
+	assertUnchanged()
+
+	// A source fails part-way through the chain
+	assert.NoError(t, os.Unsetenv("TRANSACTIONAL_LOAD_TEST__ABC"))
+	assert.NoError(t, os.WriteFile(path, []byte("%"), 0o600))
+	assert.Error(t, cfg.Load())
+	assertUnchanged()
+
+	// Unmarshalling fails in Set
+	assert.Error(t, cfg.Set("nested", "not a table"))
+	assertUnchanged()
+
+	// Keys removed from a source are gone after a successful load
+	assert.NoError(t, os.WriteFile(path, []byte("key = 'changed'"), 0o600))
+	assert.NoError(t, cfg.Load())
+	assert.Equal(t, "changed", model.Key)
+	assert.Equal(t, "", model.Nested.Foo)
+	assert.False(t, cfg.K.Exists("nested.foo"))
+	assert.Equal(t, "xyz", model.ABC)
+}
+
+func TestBestEffort(t *testing.T) {
+	dir := t.TempDir()
+	malformed := dir + "/malformed.toml"
+	assert.NoError(t, os.WriteFile(malformed, []byte("%"), 0o600))
+	t.Setenv("BEST_EFFORT_TEST__ABC", "abcd") // ABC must be length 3
+
+	sources := WithSource(
+		LocalFile[*TestModel](malformed),
+		LocalFile[*TestModel](dir+"/missing.toml"),
+		EmbeddedDefaults[*TestModel]([]byte("[nested]\nfoo = 'bar'"), FileTypeTOML),
+		Env[*TestModel]("BEST_EFFORT_TEST__"),
+	)
+
+	// Fail-fast by default
+	cfg, err := New(&TestModel{Key: "initial"}, sources)
+	assert.NoError(t, err)
+	err = cfg.Load()
+	assert.ErrorIs(t, err, ErrParse)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	cfg, err = New(&TestModel{Key: "initial"}, sources, WithBestEffort[*TestModel](true))
+	assert.NoError(t, err)
+	err = cfg.Load()
+	assert.Error(t, err)
+
+	joined, ok := err.(interface{ Unwrap() []error }) //nolint:errorlint // Testing the joined error itself.
+	if assert.True(t, ok) {
+		assert.Len(t, joined.Unwrap(), 2) // The source errors and the validation error
+	}
+
+	assert.ErrorIs(t, err, ErrParse)
+	assert.ErrorIs(t, err, ErrNotFound)
+	var verr *ValidationError
+	assert.ErrorAs(t, err, &verr)
+
+	// Nothing is applied
+	assert.Equal(t, "initial", cfg.Model().Key)
+	assert.Equal(t, "", cfg.Model().Nested.Foo)
+	assert.False(t, cfg.K.Exists("nested.foo"))
+}
@@ -0,0 +1,121 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// errNoDeferredSource is the reason a deferred source is skipped when it resolves to no source.
+var errNoDeferredSource = errors.New("deferred source resolved to no source")
+
+// Deferred is a source that is only created at load time, from the values of the other sources. This allows
+// sources to depend on values that are loaded by sources with a higher precedence, such as loading the config
+// file that is given with a flag or an environment variable:
+//
+//	WithSource(
+//		EmbeddedDefaults[*AppConfig](defaults, FileTypeTOML),
+//		Deferred(func(k *koanf.Koanf) SourceFunc[*AppConfig] {
+//			if path := k.String("config_file"); path != "" {
+//				return LocalFile[*AppConfig](path)
+//			}
+//			return nil // Nothing to load, the source is skipped
+//		}),
+//		Env[*AppConfig]("MYAPP_"),
+//		PFlags[*AppConfig](flags),
+//	)
+//
+// To bootstrap deferred sources, all other sources are loaded first, and the function is called with the result.
+// The deferred source is then loaded at its position like any other source, so the flags and environment
+// variables above still override the values from the file. The other sources are only loaded once, except
+// for the sources that use `Merged` (such as `PFlags`), which are loaded again with the deferred values.
+// Deferred sources do not see the values of other deferred sources, and are not watched.
+func Deferred[C ConfigModel](fn func(k *koanf.Koanf) SourceFunc[C]) SourceFunc[C] {
+	return func(mgr *Config[C]) (Source, error) {
+		if fn == nil {
+			return Source{}, fmt.Errorf("deferred source function cannot be nil")
+		}
+
+		src := Source{
+			Type:     SourceTypeDeferred,
+			Location: "deferred",
+			deferred: true,
+			Load: func(ctx context.Context, k *koanf.Koanf) error {
+				bootstrap := bootstrapFrom(ctx)
+				if bootstrap == nil {
+					bootstrap = koanf.New(k.Delim())
+				}
+
+				srcFunc := fn(bootstrap)
+				if srcFunc == nil {
+					markSkipped(ctx, errNoDeferredSource)
+					return nil
+				}
+				src, err := srcFunc(mgr)
+				if err == nil {
+					err = src.Type.Valid()
+				}
+				if err != nil {
+					return fmt.Errorf("failed to create deferred source: %w", err)
+				}
+
+				if state := sourceStateFrom(ctx); state != nil {
+					state.resolved = &src
+				}
+				return src.Load(ctx, k)
+			},
+		}
+		return src, nil
+	}
+}
+
+func (mgr *Config[C]) hasDeferredSources() bool {
+	for _, src := range mgr.sources {
+		if src.deferred {
+			return true
+		}
+	}
+	return false
+}
+
+// bootstrap loads all sources that are not deferred, to create the deferred sources from. Fetches holds the
+// parallel sources that are loading. The loaded sources are added to prefetched, so that the actual load does
+// not load them again, except for the sources that read the merged config, which differs in the actual load.
+// Sources that fail are left out of the bootstrap, the actual load reports their failure.
+func (mgr *Config[C]) bootstrap(
+	ctx context.Context, fetches []chan *fetchedSource, prefetched []*fetchedSource,
+) *koanf.Koanf {
+	k := mgr.newKoanf()
+	for i, src := range mgr.sources {
+		if src.deferred {
+			continue
+		}
+
+		var fetched *fetchedSource
+		if fetches[i] != nil {
+			fetched = <-fetches[i]
+		} else {
+			fetched = mgr.fetchSource(ctx, i, src, k)
+		}
+		if !fetched.state.readMerged {
+			prefetched[i] = fetched
+		}
+		if fetched.err == nil && fetched.state.skipped == nil {
+			_ = k.Merge(fetched.layer)
+		}
+	}
+	return k
+}
+
+type bootstrapKey struct{}
+
+func withBootstrap(ctx context.Context, k *koanf.Koanf) context.Context {
+	return context.WithValue(ctx, bootstrapKey{}, k)
+}
+
+func bootstrapFrom(ctx context.Context) *koanf.Koanf {
+	k, _ := ctx.Value(bootstrapKey{}).(*koanf.Koanf)
+	return k
+}
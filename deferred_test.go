@@ -0,0 +1,127 @@
+package ckoanf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type DeferredModel struct {
+	ConfigFile string `koanf:"config_file"`
+	Key        string `koanf:"key"`
+	ABC        string `koanf:"abc"`
+}
+
+func (m *DeferredModel) Validate() error {
+	return nil
+}
+
+func configFileSource(k *koanf.Koanf) SourceFunc[*DeferredModel] {
+	if path := k.String("config_file"); path != "" {
+		return LocalFile[*DeferredModel](path)
+	}
+	return nil
+}
+
+func TestDeferred(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("key = 'from_file'\nabc = 'from_file'"), 0o600))
+
+	t.Run("Path from flags", func(t *testing.T) {
+		t.Setenv("DEFERRED_TEST__KEY", "from_env")
+
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		flags.String("config_file", "", "")
+		flags.String("abc", "flag_default", "")
+		require.NoError(t, flags.Parse([]string{"--config_file", path}))
+
+		cfg, err := Init(&DeferredModel{}, WithSource(
+			EmbeddedDefaults[*DeferredModel]([]byte("key = 'default'\nabc = 'default'"), FileTypeTOML),
+			Deferred(configFileSource),
+			Env[*DeferredModel]("DEFERRED_TEST__"),
+			PFlags[*DeferredModel](flags),
+		))
+		require.NoError(t, err)
+
+		// The environment still overrides the file, and the unchanged flag default does not
+		assert.Equal(t, "from_env", cfg.Model().Key)
+		assert.Equal(t, "from_file", cfg.Model().ABC)
+		assert.Equal(t, path, cfg.Model().ConfigFile)
+
+		report := cfg.Report().Sources[1]
+		assert.Equal(t, SourceTypeLocalFile, report.Type)
+		assert.Equal(t, path, report.Location)
+		assert.Equal(t, SourceStatusLoaded, report.Status)
+
+		exp, _ := cfg.Explain("abc")
+		assert.Equal(t, Origin{Source: 1, Type: SourceTypeLocalFile, Location: path, Line: 2, Key: "abc", Value: "from_file"}, exp.Origins[1])
+	})
+
+	t.Run("Nothing to load", func(t *testing.T) {
+		cfg, err := Init(&DeferredModel{}, WithSource(
+			EmbeddedDefaults[*DeferredModel]([]byte("key = 'default'"), FileTypeTOML),
+			Deferred(configFileSource),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, "default", cfg.Model().Key)
+
+		report := cfg.Report().Sources[1]
+		assert.Equal(t, SourceStatusSkipped, report.Status)
+		assert.Equal(t, SourceTypeDeferred, report.Type)
+	})
+
+	t.Run("Failing source", func(t *testing.T) {
+		t.Setenv("DEFERRED_TEST__CONFIG_FILE", filepath.Join(t.TempDir(), "missing.toml"))
+		_, err := Init(&DeferredModel{}, WithSource(
+			Deferred(configFileSource),
+			Env[*DeferredModel]("DEFERRED_TEST__"),
+		))
+		require.ErrorIs(t, err, ErrNotFound)
+
+		var loadErr *SourceLoadError
+		require.ErrorAs(t, err, &loadErr)
+		assert.Equal(t, SourceTypeLocalFile, loadErr.Type)
+
+		_, err = Init(&DeferredModel{}, WithSource(
+			OptionalSource(Deferred(configFileSource), ErrNotFound),
+			Env[*DeferredModel]("DEFERRED_TEST__"),
+		))
+		require.NoError(t, err)
+	})
+
+	t.Run("Sources are loaded once", func(t *testing.T) {
+		var loads, parallelLoads atomic.Int32
+		countingSource := func(loads *atomic.Int32, parallel bool) Source {
+			return Source{
+				Type:     SourceTypeDefault,
+				Parallel: parallel,
+				Load: func(ctx context.Context, k *koanf.Koanf) error {
+					loads.Add(1)
+					return k.Set("config_file", path)
+				},
+			}
+		}
+
+		cfg, err := Init(&DeferredModel{},
+			withRawSource[*DeferredModel](countingSource(&loads, false)),
+			withRawSource[*DeferredModel](countingSource(&parallelLoads, true)),
+			WithSource(Deferred(configFileSource)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "from_file", cfg.Model().Key)
+		assert.Equal(t, int32(1), loads.Load())
+		assert.Equal(t, int32(1), parallelLoads.Load())
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := New(&DeferredModel{}, WithSource(Deferred[*DeferredModel](nil)))
+		assert.Error(t, err)
+	})
+}
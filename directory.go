@@ -0,0 +1,150 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/knadh/koanf/v2"
+)
+
+// Directory is a source that loads every file in the directory whose name matches the glob pattern
+// (see `filepath.Match`), such as `/etc/myapp/conf.d` and `*.toml`. If the pattern is empty, all files
+// with a supported extension are loaded. Hidden files, such as editor swap files, are ignored.
+//
+// The files are merged in lexical order of their names, so later files override earlier ones (for example
+// `10-base.toml` before `20-override.yaml`), and the filetype of each file is inferred from its extension.
+// The files can include other files, see `LocalFile`. A missing directory matches `ErrNotFound`, an empty
+// directory loads nothing.
+//
+// The directory can be watched for changed, added and removed files, see `Config.Watch`.
+func Directory[C ConfigModel](dir string, glob string) SourceFunc[C] {
+	return func(mgr *Config[C]) (Source, error) {
+		if glob != "" {
+			if _, err := filepath.Match(glob, ""); err != nil {
+				return Source{}, fmt.Errorf("invalid glob pattern %s: %w", glob, err)
+			}
+		}
+
+		location := filepath.Join(dir, glob)
+		src := Source{
+			Type:     SourceTypeDirectory,
+			Location: location,
+			Load: func(ctx context.Context, k *koanf.Koanf) error {
+				paths, err := directoryFiles(dir, glob)
+				if err == nil {
+					err = loadLocalFiles(ctx, k, paths)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to load config from directory: %w", err)
+				}
+				return nil
+			},
+			Watch: func(ctx context.Context, notify func()) error {
+				return watchDir(ctx, dir, notify)
+			},
+		}
+		return src, nil
+	}
+}
+
+// directoryFiles returns the paths of the files in the directory that match the glob, sorted by name.
+func directoryFiles(dir, glob string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &NotFoundError{Locations: []string{dir}, Err: err}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+
+		var match bool
+		if glob == "" {
+			match = isConfigFile(name)
+		} else {
+			match, _ = filepath.Match(glob, name)
+		}
+		if !match {
+			continue
+		}
+
+		// Entries may be symlinks, as in Kubernetes ConfigMap volumes.
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// watchDir watches the directory and calls notify whenever anything in it changes. If the directory
+// does not exist yet, its parent is watched until it is created.
+func watchDir(ctx context.Context, dir string, notify func()) error {
+	// Relative to the working directory, the parent of "." would be "." itself.
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("failed to watch directory %s: %w", dir, err)
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	parent := filepath.Dir(dir)
+	if err := w.Add(parent); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("failed to watch directory %s: %w", parent, err)
+	}
+	// Fails if the directory does not exist yet, it is added once it is created.
+	_ = w.Add(dir)
+
+	go func() {
+		defer w.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if event.Op == fsnotify.Chmod {
+					continue
+				}
+
+				name := filepath.Clean(event.Name)
+				if filepath.Dir(name) == parent && name != dir {
+					continue
+				}
+				if name == dir && event.Has(fsnotify.Create) {
+					_ = w.Add(dir)
+				}
+				notify()
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				// Events may have been dropped, reloading is always safe.
+				notify()
+			}
+		}
+	}()
+
+	return nil
+}
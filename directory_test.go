@@ -0,0 +1,117 @@
+package ckoanf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectory(t *testing.T) {
+	t.Run("Lexical order", func(t *testing.T) {
+		dir := t.TempDir()
+		files := map[string]string{
+			"20-override.yaml":  "key: from_yaml\n",
+			"10-base.toml":      "key = 'from_toml'\nabc = 'tml'\n",
+			"30-nested.json":    `{"nested": {"foo": "from_json"}}`,
+			".10-hidden.toml":   "key = 'hidden'",
+			"README.md":         "key = 'readme'",
+			"99-ignored.toml.d": "key = 'ignored'",
+		}
+		for name, content := range files {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+		}
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "50-dir.toml"), 0o700))
+
+		cfg, err := Init(&TestModel{}, WithSource(Directory[*TestModel](dir, "")))
+		require.NoError(t, err)
+		assert.Equal(t, "from_yaml", cfg.Model().Key)
+		assert.Equal(t, "tml", cfg.Model().ABC)
+		assert.Equal(t, "from_json", cfg.Model().Nested.Foo)
+
+		// Every file is its own origin
+		exp, ok := cfg.Explain("key")
+		require.True(t, ok)
+		assert.Equal(t, []Origin{
+			{Type: SourceTypeDirectory, Location: filepath.Join(dir, "10-base.toml"), Line: 1, Key: "key", Value: "from_toml"},
+			{Type: SourceTypeDirectory, Location: filepath.Join(dir, "20-override.yaml"), Line: 1, Key: "key", Value: "from_yaml"},
+		}, exp.Origins)
+		assert.Equal(t, 3, cfg.Report().Sources[0].Keys)
+
+		cfg, err = Init(&TestModel{}, WithSource(Directory[*TestModel](dir, "*.toml")))
+		require.NoError(t, err)
+		assert.Equal(t, "from_toml", cfg.Model().Key)
+	})
+
+	t.Run("Missing and empty", func(t *testing.T) {
+		dir := t.TempDir()
+		_, err := Init(&TestModel{}, WithSource(Directory[*TestModel](filepath.Join(dir, "conf.d"), "*.toml")))
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		_, err = Init(&TestModel{}, WithSource(
+			EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML),
+			Directory[*TestModel](dir, "*.toml"),
+		))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "a.toml"), []byte("key = "), 0o600))
+		_, err := Init(&TestModel{}, WithSource(Directory[*TestModel](dir, "*.toml")))
+		assert.ErrorIs(t, err, ErrParse)
+
+		_, err = New(&TestModel{}, WithSource(Directory[*TestModel](dir, "[")))
+		assert.Error(t, err)
+	})
+
+	t.Run("Watch added and removed files", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "conf.d")
+		cfg, err := Init(&TestModel{},
+			WithSource(
+				EmbeddedDefaults[*TestModel]([]byte("key = 'old'"), FileTypeTOML),
+				OptionalSource(Directory[*TestModel](dir, "*.toml"), ErrNotFound),
+			),
+			WithReloadDebounce[*TestModel](10*time.Millisecond),
+		)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, cfg.Watch(ctx))
+
+		// The directory does not exist yet
+		require.NoError(t, os.Mkdir(dir, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "override.toml"), []byte("key = 'new'"), 0o600))
+		assert.Eventually(t, keyIsNew(cfg), watchTimeout, 10*time.Millisecond)
+
+		require.NoError(t, os.Remove(filepath.Join(dir, "override.toml")))
+		assert.Eventually(t, func() bool { return cfg.Snapshot().Key == "old" }, watchTimeout, 10*time.Millisecond)
+	})
+	t.Run("Watch working directory", func(t *testing.T) {
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(t.TempDir()))
+		defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+		cfg, err := Init(&TestModel{},
+			WithSource(
+				EmbeddedDefaults[*TestModel]([]byte("key = 'old'"), FileTypeTOML),
+				Directory[*TestModel](".", "*.toml"),
+			),
+			WithReloadDebounce[*TestModel](10*time.Millisecond),
+		)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, cfg.Watch(ctx))
+
+		require.NoError(t, os.WriteFile("override.toml", []byte("key = 'new'"), 0o600))
+		assert.Eventually(t, keyIsNew(cfg), watchTimeout, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,134 @@
+package ckoanf
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+	"github.com/pelletier/go-toml"
+	"gopkg.in/yaml.v3"
+)
+
+// mapProvider is a koanf.Provider for an already parsed config map.
+type mapProvider map[string]interface{}
+
+func (p mapProvider) Read() (map[string]interface{}, error) {
+	return p, nil
+}
+
+func (p mapProvider) ReadBytes() ([]byte, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+// loadDocument parses a config document and loads it into the given koanf instance,
+// recording the location (and where known, the line) every value came from.
+//
+// If profiles are enabled, the profile sections in the document are left out and the section of the active
+// profile overrides the other values, see `WithProfile`.
+func loadDocument(ctx context.Context, k *koanf.Koanf, b []byte, filetype ConfigFileType, location string) error {
+	return loadProfileDocument(ctx, k, b, filetype, location, "")
+}
+
+// loadProfileDocument is like loadDocument, for a document that only applies to the given profile.
+func loadProfileDocument(
+	ctx context.Context, k *koanf.Koanf, b []byte, filetype ConfigFileType, location, profile string,
+) error {
+	values, lines, err := parseDocument(b, filetype)
+	if err != nil {
+		return &ParseError{Location: location, FileType: filetype, Err: err}
+	}
+	return loadParsedDocument(ctx, k, values, lines, location, profile)
+}
+
+// loadParsedDocument is like loadProfileDocument, for a document that is already parsed.
+func loadParsedDocument(
+	ctx context.Context, k *koanf.Koanf, values map[string]interface{}, lines map[string]int, location, profile string,
+) error {
+	values, err := extractOverrides(ctx, values, location)
+	if err != nil {
+		return err
+	}
+
+	var section map[string]interface{}
+	var sectionLines map[string]int
+	if state := sourceStateFrom(ctx); state != nil && state.profilesEnabled {
+		// The profile sections are left out even if no profile is active.
+		var defined []string
+		values, section, sectionLines, defined = splitProfiles(values, lines, state.profile)
+		markProfiles(ctx, defined...)
+	}
+
+	if err := k.Load(mapProvider(values), nil); err != nil {
+		return err
+	}
+	flat, _ := maps.Flatten(values, nil, k.Delim())
+	recordProfileOrigin(ctx, location, flat, lines, profile)
+
+	if section != nil {
+		if err := k.Load(mapProvider(section), nil); err != nil {
+			return err
+		}
+		flat, _ := maps.Flatten(section, nil, k.Delim())
+		recordProfileOrigin(ctx, location, flat, sectionLines, activeProfile(ctx))
+	}
+	return nil
+}
+
+// parseDocument parses a config document like the parser of the filetype does, and also returns
+// the line every key was defined on. Lines are only known for TOML and YAML documents.
+func parseDocument(b []byte, filetype ConfigFileType) (map[string]interface{}, map[string]int, error) {
+	if err := filetype.Valid(); err != nil {
+		return nil, nil, err
+	}
+
+	switch filetype {
+	case FileTypeTOML:
+		tree, err := toml.LoadBytes(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		lines := make(map[string]int)
+		tomlLines(tree, "", lines)
+		return tree.ToMap(), lines, nil
+	case FileTypeYAML:
+		var node yaml.Node
+		if err := yaml.Unmarshal(b, &node); err != nil {
+			return nil, nil, err
+		}
+		values := make(map[string]interface{})
+		if err := node.Decode(&values); err != nil && node.Kind != 0 {
+			return nil, nil, err
+		}
+		lines := make(map[string]int)
+		if len(node.Content) > 0 {
+			yamlLines(node.Content[0], "", lines)
+		}
+		return values, lines, nil
+	default:
+		values, err := filetype.Parser().Unmarshal(b)
+		return values, nil, err
+	}
+}
+
+func tomlLines(tree *toml.Tree, prefix string, lines map[string]int) {
+	for _, key := range tree.Keys() {
+		path := prefix + key
+		lines[path] = tree.GetPositionPath([]string{key}).Line
+		if sub, ok := tree.GetPath([]string{key}).(*toml.Tree); ok {
+			tomlLines(sub, path+defaultDelimiter, lines)
+		}
+	}
+}
+
+func yamlLines(node *yaml.Node, prefix string, lines map[string]int) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := prefix + key.Value
+		lines[path] = key.Line
+		yamlLines(value, path+defaultDelimiter, lines)
+	}
+}
@@ -0,0 +1,96 @@
+package ckoanf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNotFound matches errors of sources whose config could not be found, such as a missing file.
+	ErrNotFound = errors.New("config not found")
+	// ErrParse matches errors of config documents that could not be parsed.
+	ErrParse = errors.New("failed to parse config")
+)
+
+// SourceLoadError is returned by `Load` when a source fails to load.
+type SourceLoadError struct {
+	// Index of the source in the order the sources were added.
+	Index    int
+	Type     SourceType
+	Location string
+
+	Err error
+}
+
+func (e *SourceLoadError) Error() string {
+	if e.Location == "" {
+		return fmt.Sprintf("failed to load config from provider %d (type=%s): %v", e.Index, e.Type, e.Err)
+	}
+	return fmt.Sprintf("failed to load config from provider %d (type=%s, location=%s): %v",
+		e.Index, e.Type, e.Location, e.Err)
+}
+
+func (e *SourceLoadError) Unwrap() error {
+	return e.Err
+}
+
+// NotFoundError is returned by sources whose config could not be found.
+// It matches `ErrNotFound` with `errors.Is`.
+type NotFoundError struct {
+	// Locations that were searched.
+	Locations []string
+
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *NotFoundError) Error() string {
+	msg := "config not found at " + strings.Join(e.Locations, ", ")
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound //nolint:errorlint // Sentinel comparison.
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError is returned by sources when a config document could not be parsed.
+// It matches `ErrParse` with `errors.Is`.
+type ParseError struct {
+	Location string
+	FileType ConfigFileType
+
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse %s config from %s: %v", e.FileType, e.Location, e.Err)
+}
+
+func (e *ParseError) Is(target error) bool {
+	return target == ErrParse //nolint:errorlint // Sentinel comparison.
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// UnmarshalError is returned when the merged config can not be unmarshalled into the model.
+type UnmarshalError struct {
+	Err error
+}
+
+func (e *UnmarshalError) Error() string {
+	return "failed to unmarshal config: " + e.Err.Error()
+}
+
+func (e *UnmarshalError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,70 @@
+package ckoanf
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrors(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.toml")
+	malformed := filepath.Join(dir, "malformed.toml")
+	require.NoError(t, os.WriteFile(malformed, []byte("%"), 0o600))
+
+	defaults := EmbeddedDefaults[*TestModel]([]byte("key = 'value'"), FileTypeTOML)
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := Init(&TestModel{}, WithSource(defaults, LocalFile[*TestModel](missing)))
+		require.Error(t, err)
+
+		var loadErr *SourceLoadError
+		require.ErrorAs(t, err, &loadErr)
+		assert.Equal(t, 1, loadErr.Index)
+		assert.Equal(t, SourceTypeLocalFile, loadErr.Type)
+		assert.Equal(t, missing, loadErr.Location)
+
+		var notFound *NotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, []string{missing}, notFound.Locations)
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.NotErrorIs(t, err, ErrParse)
+	})
+
+	t.Run("Parse", func(t *testing.T) {
+		_, err := Init(&TestModel{}, WithSource(defaults, LocalFile[*TestModel](malformed)))
+		require.Error(t, err)
+
+		var parseErr *ParseError
+		require.ErrorAs(t, err, &parseErr)
+		assert.Equal(t, malformed, parseErr.Location)
+		assert.Equal(t, FileTypeTOML, parseErr.FileType)
+		assert.ErrorIs(t, err, ErrParse)
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("OptionalSource allows by kind", func(t *testing.T) {
+		_, err := Init(&TestModel{}, WithSource(defaults, OptionalSource(LocalFile[*TestModel](missing), ErrNotFound)))
+		assert.NoError(t, err)
+
+		_, err = Init(&TestModel{}, WithSource(defaults, OptionalSource(LocalFile[*TestModel](malformed), ErrNotFound)))
+		assert.ErrorIs(t, err, ErrParse)
+	})
+
+	t.Run("Unmarshal", func(t *testing.T) {
+		_, err := Init(Empty{})
+		var unmarshalErr *UnmarshalError
+		assert.ErrorAs(t, err, &unmarshalErr)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := Init(&TestModel{})
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
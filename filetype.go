@@ -2,6 +2,8 @@ package ckoanf
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/knadh/koanf/parsers/json"
@@ -19,6 +21,16 @@ const (
 	FileTypeJSON ConfigFileType = "json"
 )
 
+// configFileExtensions are the extensions of the supported config file types, in order of preference.
+//
+//nolint:gochecknoglobals // Constant list.
+var configFileExtensions = []string{".toml", ".yaml", ".yml", ".json"}
+
+// isConfigFile reports whether the file has the extension of a supported config file type.
+func isConfigFile(path string) bool {
+	return slices.Contains(configFileExtensions, filepath.Ext(path))
+}
+
 // Infer the config file type from the file path. If the file path does not
 // have a valid extension, the default file type is returned.
 //
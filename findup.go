@@ -0,0 +1,100 @@
+package ckoanf
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// vcsDirs are the directories that mark the root of a version controlled repository.
+//
+//nolint:gochecknoglobals // Constant list.
+var vcsDirs = []string{".git", ".hg", ".svn", ".jj"}
+
+// FindUpOptions configures a `FindUp` source.
+type FindUpOptions struct {
+	// Dir is the directory to start in, defaults to the working directory.
+	Dir string
+
+	// All loads every file that is found instead of only the nearest one. They are merged from the
+	// outermost to the innermost directory, so nearer files override the ones further up.
+	All bool
+
+	// StopAtVCSRoot stops at the root of the repository the directory is in (the directory that contains
+	// `.git`, `.hg`, `.svn` or `.jj`), instead of at the root of the filesystem.
+	StopAtVCSRoot bool
+}
+
+// FindUp is a source that looks for a config file with the given name in the working directory and
+// its parents, like git looks for `.git`, and loads the nearest one. For example, `FindUp(".myapprc", opts)`
+// finds `.myapprc.toml`, `.myapprc.yaml`, `.myapprc.yml` or `.myapprc.json` in the working directory and up.
+//
+// If the name has no supported extension every supported extension is tried, see `SearchFile`.
+// If no file is found, the error matches `ErrNotFound` and lists every location that was searched.
+func FindUp[C ConfigModel](name string, opts FindUpOptions) SourceFunc[C] {
+	return func(mgr *Config[C]) (Source, error) {
+		if name == "" {
+			return Source{}, fmt.Errorf("file name cannot be empty")
+		}
+
+		location := name + " in parent directories"
+		if opts.Dir != "" {
+			location = fmt.Sprintf("%s in %s and parent directories", name, opts.Dir)
+		}
+
+		src := Source{
+			Type:     SourceTypeLocalFile,
+			Location: location,
+			Load: func(ctx context.Context, k *koanf.Koanf) error {
+				dirs, err := parentDirs(opts.Dir, opts.StopAtVCSRoot)
+				if err != nil {
+					return fmt.Errorf("failed to load config from local file: %w", err)
+				}
+				return loadSearched(ctx, k, name, dirs, opts.All)
+			},
+		}
+		return src, nil
+	}
+}
+
+// parentDirs returns the directory and its parents, up to the root of the filesystem or
+// (if stopAtVCSRoot) the root of the repository. The working directory is used if dir is empty.
+func parentDirs(dir string, stopAtVCSRoot bool) ([]string, error) {
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		dir = wd
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for {
+		dirs = append(dirs, dir)
+		if stopAtVCSRoot && isVCSRoot(dir) {
+			return dirs, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dirs, nil
+		}
+		dir = parent
+	}
+}
+
+func isVCSRoot(dir string) bool {
+	for _, vcs := range vcsDirs {
+		if _, err := os.Stat(filepath.Join(dir, vcs)); err == nil {
+			return true
+		}
+	}
+	return false
+}
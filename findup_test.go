@@ -0,0 +1,86 @@
+package ckoanf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUp(t *testing.T) {
+	// root/.myapprc.toml, root/repo/.git, root/repo/.myapprc.yaml, root/repo/sub/.myapprc.json, root/repo/sub/deeper
+	root := t.TempDir()
+	repo := filepath.Join(root, "repo")
+	sub := filepath.Join(repo, "sub")
+	deeper := filepath.Join(sub, "deeper")
+	require.NoError(t, os.MkdirAll(deeper, 0o700))
+	require.NoError(t, os.Mkdir(filepath.Join(repo, ".git"), 0o700))
+
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".myapprc.toml"), []byte("key = 'root'\nabc = 'rot'\n[nested]\nfoo = 'root'"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, ".myapprc.yaml"), []byte("key: repo\nabc: rep"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(sub, ".myapprc.json"), []byte(`{"key": "sub"}`), 0o600))
+
+	t.Run("Nearest", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(FindUp[*TestModel](".myapprc", FindUpOptions{Dir: deeper})))
+		require.NoError(t, err)
+		assert.Equal(t, "sub", cfg.Model().Key)
+		assert.Empty(t, cfg.Model().ABC)
+	})
+
+	t.Run("Working directory", func(t *testing.T) {
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(repo))
+		defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+		cfg, err := Init(&TestModel{}, WithSource(FindUp[*TestModel](".myapprc.yaml", FindUpOptions{})))
+		require.NoError(t, err)
+		assert.Equal(t, "repo", cfg.Model().Key)
+	})
+
+	t.Run("All", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(FindUp[*TestModel](".myapprc", FindUpOptions{Dir: deeper, All: true})))
+		require.NoError(t, err)
+		assert.Equal(t, "sub", cfg.Model().Key)
+		assert.Equal(t, "rep", cfg.Model().ABC)
+		assert.Equal(t, "root", cfg.Model().Nested.Foo)
+
+		exp, _ := cfg.Explain("key")
+		locations := make([]string, len(exp.Origins))
+		for i, origin := range exp.Origins {
+			locations[i] = origin.Location
+		}
+		assert.Equal(t, []string{
+			filepath.Join(root, ".myapprc.toml"),
+			filepath.Join(repo, ".myapprc.yaml"),
+			filepath.Join(sub, ".myapprc.json"),
+		}, locations)
+	})
+
+	t.Run("Stop at VCS root", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(
+			FindUp[*TestModel](".myapprc", FindUpOptions{Dir: deeper, All: true, StopAtVCSRoot: true})))
+		require.NoError(t, err)
+		assert.Equal(t, "rep", cfg.Model().ABC)
+		assert.Empty(t, cfg.Model().Nested.Foo)
+
+		_, err = Init(&TestModel{}, WithSource(
+			FindUp[*TestModel](".myapprc.toml", FindUpOptions{Dir: deeper, StopAtVCSRoot: true})))
+		require.ErrorIs(t, err, ErrNotFound)
+
+		var notFound *NotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, []string{
+			filepath.Join(deeper, ".myapprc.toml"),
+			filepath.Join(sub, ".myapprc.toml"),
+			filepath.Join(repo, ".myapprc.toml"),
+		}, notFound.Locations)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := New(&TestModel{}, WithSource(FindUp[*TestModel]("", FindUpOptions{})))
+		assert.Error(t, err)
+	})
+}
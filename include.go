@@ -0,0 +1,117 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	// includeKey is the key of the files a local config file includes.
+	includeKey = "include"
+	// maxIncludeDepth is how deeply included files may include other files.
+	maxIncludeDepth = 8
+)
+
+// ErrIncludeCycle matches errors of local config files that (indirectly) include themselves.
+var ErrIncludeCycle = errors.New("include cycle")
+
+// loadFile loads a local config file, after the files it includes. The values of the file, and of the files
+// it includes, only apply to the given profile if it is set. Chain lists the absolute paths of the files
+// that include this one, outermost first.
+func loadFile(ctx context.Context, k *koanf.Koanf, path, profile string, chain []string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(chain, abs) {
+		return fmt.Errorf("%w: %s -> %s", ErrIncludeCycle, strings.Join(chain, " -> "), abs)
+	}
+	if len(chain) > maxIncludeDepth {
+		return fmt.Errorf("failed to include %s: includes are nested deeper than %d files", path, maxIncludeDepth)
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &NotFoundError{Locations: []string{path}, Err: err}
+	}
+	if err != nil {
+		return err
+	}
+
+	filetype := inferConfigFiletype(path)
+	values, lines, err := parseDocument(b, filetype)
+	if err != nil {
+		return &ParseError{Location: path, FileType: filetype, Err: err}
+	}
+
+	if include, ok := values[includeKey]; ok {
+		includes, err := includePaths(filepath.Dir(path), include)
+		if err != nil {
+			return fmt.Errorf("invalid %s in %s: %w", includeKey, path, err)
+		}
+		delete(values, includeKey)
+
+		chain = append(slices.Clip(chain), abs)
+		for _, included := range includes {
+			err := loadFile(ctx, k, included, profile, chain)
+			if errors.Is(err, ErrNotFound) {
+				// Only the file itself can be not found, a missing include is a mistake in the including file.
+				return fmt.Errorf("failed to include %s in %s: %v", included, path, err) //nolint:errorlint // See above.
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return loadParsedDocument(ctx, k, values, lines, path, profile)
+}
+
+// includePaths returns the paths of the files the include value of a file in the given directory refers to,
+// in order. Glob patterns are expanded, in lexical order.
+func includePaths(dir string, include interface{}) ([]string, error) {
+	var patterns []string
+	switch v := include.(type) {
+	case string:
+		patterns = []string{v}
+	case []interface{}:
+		for _, elem := range v {
+			pattern, ok := elem.(string)
+			if !ok {
+				return nil, fmt.Errorf("must be a path or a list of paths, got %T", elem)
+			}
+			patterns = append(patterns, pattern)
+		}
+	default:
+		return nil, fmt.Errorf("must be a path or a list of paths, got %T", include)
+	}
+
+	var paths []string
+	for _, pattern := range patterns {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(dir, pattern)
+		}
+		if !strings.ContainsAny(pattern, `*?[`) {
+			paths = append(paths, pattern)
+			continue
+		}
+
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %s: %w", pattern, err)
+		}
+		for _, match := range matches {
+			if info, err := os.Stat(match); err == nil && !info.IsDir() {
+				paths = append(paths, match)
+			}
+		}
+	}
+	return paths, nil
+}
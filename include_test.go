@@ -0,0 +1,109 @@
+package ckoanf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInclude(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	write("base.toml", "key = 'base'\nabc = 'bas'\n[nested]\nfoo = 'base'")
+	write("secrets/a.yaml", "nested:\n  foo: secret_a\n")
+	write("secrets/b.json", `{"key": "secret_b"}`)
+	path := write("config.toml", "include = ['base.toml', 'secrets/*']\nabc = 'cfg'")
+
+	cfg, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](path)))
+	require.NoError(t, err)
+	assert.Equal(t, "secret_b", cfg.Model().Key)
+	assert.Equal(t, "cfg", cfg.Model().ABC)
+	assert.Equal(t, "secret_a", cfg.Model().Nested.Foo)
+	assert.False(t, cfg.K.Exists("include"))
+
+	// Every included file is its own location, and the including file overrides them
+	exp, ok := cfg.Explain("abc")
+	require.True(t, ok)
+	assert.Equal(t, []Origin{
+		{Source: 0, Type: SourceTypeLocalFile, Location: filepath.Join(dir, "base.toml"), Line: 2, Key: "abc", Value: "bas"},
+		{Source: 0, Type: SourceTypeLocalFile, Location: path, Line: 2, Key: "abc", Value: "cfg"},
+	}, exp.Origins)
+
+	exp, ok = cfg.Explain("key")
+	require.True(t, ok)
+	require.Len(t, exp.Origins, 2)
+	assert.Equal(t, filepath.Join(dir, "secrets", "b.json"), exp.Origins[1].Location)
+
+	t.Run("Nested", func(t *testing.T) {
+		write("nested/inner.toml", "key = 'inner'\nabc = 'inr'")
+		write("nested/outer.yaml", "include: inner.toml\nkey: outer\n")
+		path := write("nested.toml", "include = 'nested/outer.yaml'")
+
+		cfg, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](path)))
+		require.NoError(t, err)
+		assert.Equal(t, "outer", cfg.Model().Key)
+		assert.Equal(t, "inr", cfg.Model().ABC)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		path := write("missing.toml", "include = 'does_not_exist.toml'\nabc = 'mis'")
+
+		_, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](path)))
+		assert.ErrorContains(t, err, "does_not_exist.toml")
+		// Only a missing top-level file is not found
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Missing file in optional source", func(t *testing.T) {
+		path := write("optional.toml", "include = ['bse.toml']\nkey = 'opt'\nabc = 'opt'")
+
+		cfg, err := New(&TestModel{}, WithSource(OptionalSource(LocalFile[*TestModel](path), ErrNotFound)))
+		require.NoError(t, err)
+		err = cfg.Load()
+		assert.ErrorContains(t, err, "bse.toml")
+		require.Len(t, cfg.Report().Sources, 1)
+		assert.Equal(t, SourceStatusFailed, cfg.Report().Sources[0].Status)
+	})
+
+	t.Run("No glob matches", func(t *testing.T) {
+		path := write("empty_glob.toml", "include = 'none/*.toml'\nkey = 'k'\nabc = 'emp'")
+
+		cfg, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](path)))
+		require.NoError(t, err)
+		assert.Equal(t, "emp", cfg.Model().ABC)
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		write("cycle/a.toml", "include = 'b.toml'")
+		path := write("cycle/b.toml", "include = 'a.toml'")
+
+		_, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](path)))
+		assert.ErrorIs(t, err, ErrIncludeCycle)
+	})
+
+	t.Run("Depth limit", func(t *testing.T) {
+		for i := 0; i < maxIncludeDepth+1; i++ {
+			write(filepath.Join("deep", string(rune('a'+i))+".toml"), "include = '"+string(rune('a'+i+1))+".toml'")
+		}
+		write(filepath.Join("deep", string(rune('a'+maxIncludeDepth+1))+".toml"), "key = 'deep'")
+
+		_, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](filepath.Join(dir, "deep", "a.toml"))))
+		assert.ErrorContains(t, err, "nested deeper than")
+	})
+
+	t.Run("Invalid include", func(t *testing.T) {
+		path := write("invalid.toml", "include = 1")
+
+		_, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](path)))
+		assert.ErrorContains(t, err, "invalid include")
+	})
+}
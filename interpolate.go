@@ -0,0 +1,261 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+var (
+	// ErrUnresolvedReference matches references in config values that could not be resolved.
+	ErrUnresolvedReference = errors.New("unresolved reference")
+	// ErrReferenceCycle matches references in config values that (indirectly) refer to themselves.
+	ErrReferenceCycle = errors.New("reference cycle")
+)
+
+// InterpolationError is returned by `Load` when a reference in a config value can not be resolved.
+type InterpolationError struct {
+	// Key path of the value that contains the reference.
+	Key string
+	// Reference as it was written between `${` and `}`.
+	Reference string
+
+	Err error
+}
+
+func (e *InterpolationError) Error() string {
+	return fmt.Sprintf("failed to interpolate config key %s: ${%s}: %v", e.Key, e.Reference, e.Err)
+}
+
+func (e *InterpolationError) Unwrap() error {
+	return e.Err
+}
+
+// interpolator resolves the references in the values of a merged config.
+type interpolator struct {
+	ctx       context.Context //nolint:containedctx // Only lives for a single load.
+	values    map[string]interface{}
+	resolvers map[string]Resolver
+	isSecret  func(key string) bool
+
+	resolved map[string]interface{}
+	failed   map[string]error
+	// Keys whose value contains a secret, because it used a resolver or referred to a secret key.
+	secret map[string]bool
+	// Keys that are currently being resolved, to detect cycles.
+	stack []string
+}
+
+// interpolate replaces the references in all string values of the merged config, see `WithInterpolation`.
+// All unresolvable references are reported, joined with `errors.Join`.
+// Keys whose values now contain secrets are marked secret.
+func (mgr *Config[C]) interpolate(ctx context.Context, k *koanf.Koanf) error {
+	in := &interpolator{
+		ctx:       ctx,
+		values:    k.All(),
+		resolvers: mgr.resolvers,
+		isSecret:  mgr.IsSecret,
+		resolved:  make(map[string]interface{}),
+		failed:    make(map[string]error),
+		secret:    make(map[string]bool),
+	}
+
+	var errs []error
+	reported := make(map[error]bool)
+	for _, key := range sortedKeys(in.values) {
+		value, err := in.resolveKey(key)
+		if err != nil {
+			// A failing reference makes all values that refer to it fail with the same error.
+			if !reported[err] {
+				reported[err] = true
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		if in.secret[key] && !mgr.IsSecret(key) {
+			mgr.markResolvedSecret(key)
+		}
+
+		if value, changed := value.(interpolated); changed {
+			// Delete first, as the type of the value may have changed which fails a strict merge.
+			k.Delete(key)
+			if err := k.Set(key, value.value); err != nil {
+				errs = append(errs, &InterpolationError{Key: key, Err: err})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// interpolated wraps values that contained references, so that unchanged values can be skipped.
+type interpolated struct {
+	value interface{}
+}
+
+func unwrapInterpolated(value interface{}) interface{} {
+	if v, ok := value.(interpolated); ok {
+		return v.value
+	}
+	return value
+}
+
+func (in *interpolator) resolveKey(key string) (interface{}, error) {
+	if value, ok := in.resolved[key]; ok {
+		return value, nil
+	}
+	if err, ok := in.failed[key]; ok {
+		return nil, err
+	}
+
+	in.stack = append(in.stack, key)
+	value, err := in.resolveValue(key, in.values[key])
+	in.stack = in.stack[:len(in.stack)-1]
+
+	if err != nil {
+		in.failed[key] = err
+		return nil, err
+	}
+	in.resolved[key] = value
+	return value, nil
+}
+
+func (in *interpolator) resolveValue(key string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return in.resolveString(key, v)
+	case []interface{}:
+		var changed bool
+		out := make([]interface{}, len(v))
+		for i, elem := range v {
+			resolved, err := in.resolveValue(key, elem)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := resolved.(interpolated); ok {
+				changed = true
+			}
+			out[i] = unwrapInterpolated(resolved)
+		}
+		if changed {
+			return interpolated{out}, nil
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
+
+// resolveString resolves all references in the string. If the string consists of a single reference,
+// the value it refers to is returned as is, so that references to non-string values keep their type.
+// `$${` escapes a literal `${`.
+func (in *interpolator) resolveString(key, s string) (interface{}, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	rest := s
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			b.WriteString(rest)
+			break
+		}
+		if start > 0 && rest[start-1] == '$' {
+			b.WriteString(rest[:start-1])
+			b.WriteString("${")
+			rest = rest[start+2:]
+			continue
+		}
+
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			return nil, &InterpolationError{Key: key, Reference: rest[start+2:], Err: errors.New("missing closing brace")}
+		}
+		end += start
+
+		ref := rest[start+2 : end]
+		value, err := in.resolveReference(key, ref)
+		if err != nil {
+			return nil, err
+		}
+		if start == 0 && end == len(s)-1 {
+			return interpolated{value}, nil
+		}
+
+		b.WriteString(rest[:start])
+		b.WriteString(fmt.Sprint(value))
+		rest = rest[end+1:]
+	}
+	return interpolated{b.String()}, nil
+}
+
+// resolveReference resolves a single reference, which is one of
+//   - `env:NAME`, the value of an environment variable.
+//   - `scheme:argument`, resolved by the resolver registered for the scheme.
+//   - `key.path`, the value of another key in the config, or if there is no such key, the environment variable.
+//
+// All can be followed by `:-default`, which is used if the reference is not set or empty.
+func (in *interpolator) resolveReference(key, ref string) (interface{}, error) {
+	name, def, hasDefault := strings.Cut(ref, ":-")
+	scheme, arg, hasScheme := strings.Cut(name, ":")
+
+	var value interface{}
+	var found bool
+	var notFound error
+	if envName, ok := strings.CutPrefix(name, "env:"); ok {
+		value, found = os.LookupEnv(envName)
+	} else if resolver, ok := in.resolvers[scheme]; ok && hasScheme {
+		resolved, err := resolver.Resolve(in.ctx, arg)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return nil, &InterpolationError{Key: key, Reference: ref, Err: err}
+		}
+		value, found, notFound = resolved, err == nil, err
+		in.secret[key] = true
+	} else if _, ok := in.values[name]; ok {
+		if err := in.checkCycle(key, ref, name); err != nil {
+			return nil, err
+		}
+		resolved, err := in.resolveKey(name)
+		if err != nil {
+			return nil, err
+		}
+		value, found = unwrapInterpolated(resolved), true
+		if in.secret[name] || in.isSecret(name) {
+			in.secret[key] = true
+		}
+	} else {
+		value, found = os.LookupEnv(name)
+	}
+
+	if hasDefault && (!found || value == "") {
+		return def, nil
+	}
+	if !found {
+		err := ErrUnresolvedReference
+		if notFound != nil {
+			err = fmt.Errorf("%w: %w", err, notFound)
+		}
+		return nil, &InterpolationError{Key: key, Reference: ref, Err: err}
+	}
+	return value, nil
+}
+
+func (in *interpolator) checkCycle(key, ref, name string) error {
+	for i, k := range in.stack {
+		if k == name {
+			cycle := append(append([]string{}, in.stack[i:]...), name)
+			return &InterpolationError{
+				Key:       key,
+				Reference: ref,
+				Err:       fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(cycle, " -> ")),
+			}
+		}
+	}
+	return nil
+}
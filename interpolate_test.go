@@ -0,0 +1,105 @@
+package ckoanf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type InterpolationModel struct {
+	URL     string   `koanf:"url"`
+	Port    int      `koanf:"port"`
+	Hosts   []string `koanf:"hosts"`
+	Region  string   `koanf:"region"`
+	Literal string   `koanf:"literal"`
+	DB      struct {
+		Host string `koanf:"host"`
+		Port int    `koanf:"port"`
+	} `koanf:"db"`
+}
+
+func (m *InterpolationModel) Validate() error {
+	return nil
+}
+
+func initInterpolated(t *testing.T, doc string) (*Config[*InterpolationModel], error) {
+	t.Helper()
+	return Init(&InterpolationModel{},
+		WithInterpolation[*InterpolationModel](true),
+		WithSource(EmbeddedDefaults[*InterpolationModel]([]byte(doc), FileTypeTOML)),
+	)
+}
+
+func TestInterpolation(t *testing.T) {
+	t.Setenv("INTERPOLATION_TEST_HOST", "db.internal")
+	t.Setenv("INTERPOLATION_TEST_EMPTY", "")
+
+	t.Run("Resolves references", func(t *testing.T) {
+		cfg, err := initInterpolated(t, `
+url = "postgres://${INTERPOLATION_TEST_HOST}:${db.port}/app"
+port = "${db.port}"
+hosts = ["${db.host}", "${env:INTERPOLATION_TEST_HOST}"]
+region = "${INTERPOLATION_TEST_EMPTY:-eu-west-1}"
+literal = "$${not.a.reference}"
+
+[db]
+host = "${env:INTERPOLATION_TEST_HOST}"
+port = 5432
+`)
+		require.NoError(t, err)
+
+		model := cfg.Model()
+		assert.Equal(t, "postgres://db.internal:5432/app", model.URL)
+		assert.Equal(t, 5432, model.Port)
+		assert.Equal(t, []string{"db.internal", "db.internal"}, model.Hosts)
+		assert.Equal(t, "eu-west-1", model.Region)
+		assert.Equal(t, "${not.a.reference}", model.Literal)
+		assert.Equal(t, "db.internal", model.DB.Host)
+		assert.Equal(t, int64(5432), cfg.K.Get("port"))
+	})
+
+	t.Run("Disabled by default", func(t *testing.T) {
+		cfg, err := Init(&InterpolationModel{},
+			WithSource(EmbeddedDefaults[*InterpolationModel]([]byte(`url = "${db.host}"`), FileTypeTOML)))
+		require.NoError(t, err)
+		assert.Equal(t, "${db.host}", cfg.Model().URL)
+	})
+
+	t.Run("Unresolved", func(t *testing.T) {
+		_, err := initInterpolated(t, `
+url = "${INTERPOLATION_TEST_MISSING}"
+region = "${env:INTERPOLATION_TEST_MISSING}"
+literal = "${url}"
+`)
+		require.ErrorIs(t, err, ErrUnresolvedReference)
+
+		var ierr *InterpolationError
+		require.ErrorAs(t, err, &ierr)
+		assert.Equal(t, "url", ierr.Key)
+		assert.Equal(t, "INTERPOLATION_TEST_MISSING", ierr.Reference)
+		// literal refers to url, so its failure is only reported once
+		assert.Contains(t, err.Error(), "failed to interpolate config key url: ${INTERPOLATION_TEST_MISSING}: unresolved reference\n"+
+			"failed to interpolate config key region: ${env:INTERPOLATION_TEST_MISSING}: unresolved reference")
+		assert.NotContains(t, err.Error(), "config key literal")
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		_, err := initInterpolated(t, `
+url = "${x.a}"
+
+[x]
+a = "${x.b}"
+b = "${x.a}"
+`)
+		require.ErrorIs(t, err, ErrReferenceCycle)
+		assert.EqualError(t, err, "failed to load config: failed to interpolate config key x.b: ${x.a}: reference cycle: x.a -> x.b -> x.a")
+	})
+
+	t.Run("Missing closing brace", func(t *testing.T) {
+		_, err := initInterpolated(t, `url = "${db.host"`)
+		var ierr *InterpolationError
+		require.ErrorAs(t, err, &ierr)
+		assert.Equal(t, "url", ierr.Key)
+	})
+}
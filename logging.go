@@ -0,0 +1,151 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// Redacted replaces the values of secrets in logs and other output.
+const Redacted = "[REDACTED]"
+
+// WithLogger sets a logger that the config manager logs its activity to, such as loading sources,
+// skipped optional sources, overridden values, validation failures, reloads and `Set` calls.
+// Most events are logged at debug level, values of secrets are redacted (see `WithSecretKeys`).
+func WithLogger[C ConfigModel](logger *slog.Logger) Option[C] {
+	return func(mgr *Config[C]) error {
+		mgr.logger = logger
+		return nil
+	}
+}
+
+// WithSecretKeys marks the given key paths (and all keys below them) as secret, a `*` segment matches
+// any key. The values of secrets are redacted wherever the config manager outputs them, such as in logs.
+//
+// Fields of the model of type `Secret` or tagged `secret:"true"` are secret without being marked.
+func WithSecretKeys[C ConfigModel](keys ...string) Option[C] {
+	return func(mgr *Config[C]) error {
+		mgr.secretKeys = append(mgr.secretKeys, keys...)
+		return nil
+	}
+}
+
+// IsSecret reports whether the value at the given key path is a secret, because it (or a key above it)
+// was marked with `WithSecretKeys`, because it is a `Secret` field or tagged `secret:"true"` in the model,
+// or because its value was resolved by a `Resolver`.
+func (mgr *Config[C]) IsSecret(key string) bool {
+	for _, secret := range mgr.secretKeys {
+		if matchesSecretKey(key, secret) {
+			return true
+		}
+	}
+	if resolved := mgr.resolvedSecrets.Load(); resolved != nil {
+		for _, secret := range *resolved {
+			if matchesSecretKey(key, secret) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// markResolvedSecret marks the key as secret, because its value was resolved by a `Resolver`.
+// Keys stay secret for later loads, even if they no longer use a resolver.
+func (mgr *Config[C]) markResolvedSecret(key string) {
+	var keys []string
+	if resolved := mgr.resolvedSecrets.Load(); resolved != nil {
+		keys = append(keys, *resolved...)
+	}
+	keys = append(keys, key)
+	mgr.resolvedSecrets.Store(&keys)
+}
+
+// redact returns the value, or `Redacted` if the key is a secret.
+func (mgr *Config[C]) redact(key string, value interface{}) interface{} {
+	if mgr.IsSecret(key) {
+		return Redacted
+	}
+	return value
+}
+
+func (mgr *Config[C]) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if mgr.logger == nil {
+		return
+	}
+	mgr.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func sourceAttrs(i int, src Source) []slog.Attr {
+	return []slog.Attr{
+		slog.Int("index", i),
+		slog.String("type", src.Type.String()),
+		slog.String("location", src.Location),
+	}
+}
+
+// logSourceReport logs the outcome of loading a single source.
+func (mgr *Config[C]) logSourceReport(ctx context.Context, report SourceReport) {
+	attrs := []slog.Attr{
+		slog.Int("index", report.Index),
+		slog.String("type", report.Type.String()),
+		slog.String("location", report.Location),
+		slog.Duration("duration", report.Duration),
+	}
+
+	switch report.Status {
+	case SourceStatusFailed:
+		mgr.log(ctx, slog.LevelWarn, "failed to load config source", append(attrs, slog.String("error", report.Err.Error()))...)
+	case SourceStatusCached:
+		mgr.log(ctx, slog.LevelWarn, "loaded config source from cache", append(attrs, slog.String("error", report.Err.Error()))...)
+	case SourceStatusSkipped:
+		mgr.log(ctx, slog.LevelInfo, "skipped optional config source", append(attrs, slog.String("error", report.Err.Error()))...)
+	default:
+		mgr.log(ctx, slog.LevelDebug, "loaded config source", append(attrs, slog.Int("keys", report.Keys))...)
+	}
+}
+
+// logOverrides logs the values of a source that override values of earlier sources.
+func (mgr *Config[C]) logOverrides(ctx context.Context, merged *koanf.Koanf, origins []Origin) {
+	if mgr.logger == nil || !mgr.logger.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+
+	for _, origin := range origins {
+		if !merged.Exists(origin.Key) {
+			continue
+		}
+		mgr.log(ctx, slog.LevelDebug, "config value overridden",
+			slog.String("key", origin.Key),
+			slog.Any("previous", mgr.redact(origin.Key, merged.Get(origin.Key))),
+			slog.Any("value", mgr.redact(origin.Key, origin.Value)),
+			slog.String("type", origin.Type.String()),
+			slog.String("location", origin.Location),
+		)
+	}
+}
+
+// logLoadError logs a failed load, with every invalid field if validation failed.
+func (mgr *Config[C]) logLoadError(ctx context.Context, err error) {
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		mgr.log(ctx, slog.LevelWarn, "failed to load config", slog.String("error", err.Error()))
+		return
+	}
+
+	if len(verr.Fields) == 0 {
+		mgr.log(ctx, slog.LevelWarn, "config failed validation", slog.String("error", verr.Err.Error()))
+	}
+	for _, field := range verr.Fields {
+		attrs := []slog.Attr{
+			slog.String("key", field.Key),
+			slog.Any("value", mgr.redact(field.Key, field.Value)),
+			slog.String("error", field.Err.Error()),
+		}
+		if field.Origin != nil {
+			attrs = append(attrs, slog.String("type", field.Origin.Type.String()), slog.String("location", field.Origin.Location))
+		}
+		mgr.log(ctx, slog.LevelWarn, "config failed validation", attrs...)
+	}
+}
@@ -0,0 +1,96 @@
+package ckoanf
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logMessages(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	t.Setenv("LOGGER_TEST__NESTED__FOO", "hunter2")
+	t.Setenv("LOGGER_TEST__KEY", "from_env")
+
+	cfg, err := Init(&TestModel{},
+		WithLogger[*TestModel](logger),
+		WithSecretKeys[*TestModel]("nested"),
+		WithSource(
+			EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML),
+			OptionalSource(LocalFile[*TestModel](filepath.Join(t.TempDir(), "missing.toml"))),
+			Env[*TestModel]("LOGGER_TEST__"),
+		),
+	)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Set("nested.foo", "s3cret"))
+	require.NoError(t, cfg.Set("abc", "xyz"))
+	require.Error(t, cfg.Set("nested", "not a table"))
+
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.NotContains(t, buf.String(), "s3cret")
+	assert.NotContains(t, buf.String(), `"bar"`)
+
+	var msgs []string
+	var overrides []map[string]interface{}
+	for _, record := range logMessages(t, &buf) {
+		msgs = append(msgs, record["msg"].(string))
+		if record["msg"] == "config value overridden" {
+			overrides = append(overrides, record)
+		}
+	}
+	assert.Equal(t, []string{
+		"loading config source", "loaded config source",
+		"loading config source", "skipped optional config source",
+		"loading config source", "config value overridden", "config value overridden", "loaded config source",
+		"loaded config",
+		"set config value", "set config value", "failed to set config value",
+	}, msgs)
+
+	require.Len(t, overrides, 2)
+	assert.Equal(t, "key", overrides[0]["key"])
+	assert.Equal(t, "value", overrides[0]["previous"])
+	assert.Equal(t, "from_env", overrides[0]["value"])
+	assert.Equal(t, "LOGGER_TEST__KEY", overrides[0]["location"])
+	assert.Equal(t, "nested.foo", overrides[1]["key"])
+	assert.Equal(t, Redacted, overrides[1]["previous"])
+	assert.Equal(t, Redacted, overrides[1]["value"])
+
+	t.Run("Validation failures", func(t *testing.T) {
+		buf.Reset()
+		_, err := Init(&MyExampleConfig{},
+			WithLogger[*MyExampleConfig](logger),
+			WithSecretKeys[*MyExampleConfig]("address.country_code"),
+			WithSource(EmbeddedDefaults[*MyExampleConfig]([]byte("port = 1\n[address]\ncountry_code = 'SECRET'"), FileTypeTOML)),
+		)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "SECRET")
+		assert.NotContains(t, buf.String(), "SECRET")
+
+		var failures []string
+		for _, record := range logMessages(t, &buf) {
+			if record["msg"] == "config failed validation" {
+				failures = append(failures, record["key"].(string))
+			}
+		}
+		assert.Equal(t, []string{"address.country_code", "address.language"}, failures)
+	})
+}
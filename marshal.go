@@ -0,0 +1,64 @@
+package ckoanf
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// MarshalOptions configures how `Config.Marshal` renders the config.
+type MarshalOptions struct {
+	// IncludeSecrets renders the values of secrets as they are. By default they are replaced with `Redacted`.
+	IncludeSecrets bool
+
+	// OmitDefaults leaves out keys whose value is the same as the one set by the default sources
+	// (`EmbeddedDefaults`), so that only what was configured on top of the defaults remains.
+	OmitDefaults bool
+}
+
+// Marshal renders the current effective config, as merged from all sources, in the given file type.
+//
+// Keys are always sorted, so the output is deterministic and can be diffed. The values of secrets are
+// redacted, unless `IncludeSecrets` is set.
+func (mgr *Config[C]) Marshal(filetype ConfigFileType, opts MarshalOptions) ([]byte, error) {
+	if err := filetype.Valid(); err != nil {
+		return nil, err
+	}
+
+	snap := mgr.current.Load()
+	var defaults map[string]interface{}
+	if opts.OmitDefaults {
+		defaults = defaultValues(snap.origins)
+	}
+
+	out := koanf.New(defaultDelimiter)
+	for key, value := range snap.k.All() {
+		if def, ok := defaults[key]; ok && reflect.DeepEqual(def, value) {
+			continue
+		}
+		if !opts.IncludeSecrets && mgr.IsSecret(key) {
+			value = Redacted
+		}
+		if err := out.Set(key, value); err != nil {
+			return nil, fmt.Errorf("failed to marshal config key %s: %w", key, err)
+		}
+	}
+
+	b, err := out.Marshal(filetype.Parser())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config as %s: %w", filetype, err)
+	}
+	return b, nil
+}
+
+// defaultValues returns the flattened values set by default sources, later ones taking precedence.
+func defaultValues(origins []Origin) map[string]interface{} {
+	defaults := make(map[string]interface{})
+	for _, origin := range origins {
+		if origin.Type == SourceTypeDefault {
+			defaults[origin.Key] = origin.Value
+		}
+	}
+	return defaults
+}
@@ -0,0 +1,62 @@
+package ckoanf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Setenv("MARSHAL_TEST__KEY", "from_env")
+	t.Setenv("MARSHAL_TEST__EXTRA", "extra")
+
+	cfg, err := Init(&TestModel{},
+		WithSource(
+			EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML),
+			Env[*TestModel]("MARSHAL_TEST__"),
+		),
+		WithSecretKeys[*TestModel]("nested.foo"),
+	)
+	require.NoError(t, err)
+
+	t.Run("TOML", func(t *testing.T) {
+		b, err := cfg.Marshal(FileTypeTOML, MarshalOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "abc = \"def\"\nextra = \"extra\"\nkey = \"from_env\"\n\n[nested]\n  foo = \"[REDACTED]\"\n", string(b))
+	})
+
+	t.Run("Include secrets", func(t *testing.T) {
+		b, err := cfg.Marshal(FileTypeTOML, MarshalOptions{IncludeSecrets: true})
+		require.NoError(t, err)
+		assert.Equal(t, "abc = \"def\"\nextra = \"extra\"\nkey = \"from_env\"\n\n[nested]\n  foo = \"bar\"\n", string(b))
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		b, err := cfg.Marshal(FileTypeYAML, MarshalOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "abc: def\nextra: extra\nkey: from_env\nnested:\n    foo: '[REDACTED]'\n", string(b))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		b, err := cfg.Marshal(FileTypeJSON, MarshalOptions{OmitDefaults: true})
+		require.NoError(t, err)
+		// extra is set by the environment as well, but to its default value
+		assert.Equal(t, `{"key":"from_env"}`, string(b))
+	})
+
+	t.Run("Deterministic", func(t *testing.T) {
+		first, err := cfg.Marshal(FileTypeTOML, MarshalOptions{})
+		require.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			b, err := cfg.Marshal(FileTypeTOML, MarshalOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, string(first), string(b))
+		}
+	})
+
+	t.Run("Invalid file type", func(t *testing.T) {
+		_, err := cfg.Marshal("ini", MarshalOptions{})
+		assert.Error(t, err)
+	})
+}
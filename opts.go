@@ -1,6 +1,9 @@
 package ckoanf
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 const defaultDelimiter = "."
 
@@ -15,6 +18,50 @@ func WithValidation[C ConfigModel](v bool) Option[C] {
 	}
 }
 
+// WithBestEffort enables or disables best-effort loading, which is disabled by default.
+//
+// By default `Load` stops at the first source that fails. In best-effort mode every source is attempted,
+// and what could be loaded is still unmarshalled and validated, so that all problems are reported at once.
+// The returned error joins all failures with `errors.Join`, use `errors.As` to inspect them.
+// The config is only replaced if there were no failures at all.
+func WithBestEffort[C ConfigModel](v bool) Option[C] {
+	return func(mgr *Config[C]) error {
+		mgr.bestEffort = v
+		return nil
+	}
+}
+
+// WithInterpolation enables or disables interpolation of references in config values, which is disabled by default.
+//
+// When enabled, references in string values are resolved after all sources are merged and before the config
+// is unmarshalled:
+//   - `${env:NAME}` is replaced by the environment variable NAME.
+//   - `${key.path}` is replaced by the value of another key, or the environment variable if there is no such key.
+//   - `${scheme:argument}` is resolved by the resolver registered for the scheme, see `WithResolver`.
+//   - `${NAME:-default}` falls back to default if the reference is not set or empty.
+//
+// A value that consists of a single reference gets the value it refers to, including its type.
+// Use `$${` for a literal `${`. References that can not be resolved, or that refer to themselves,
+// fail the load with an `InterpolationError`.
+func WithInterpolation[C ConfigModel](v bool) Option[C] {
+	return func(mgr *Config[C]) error {
+		mgr.interpolation = v
+		return nil
+	}
+}
+
+// WithLoadTimeout sets how long a single `Load` (including reloads) may take, which defaults to 10 seconds.
+// Sources get a context that is done when the timeout expires. To limit single sources, see `Timeout`.
+func WithLoadTimeout[C ConfigModel](d time.Duration) Option[C] {
+	return func(mgr *Config[C]) error {
+		if d <= 0 {
+			return fmt.Errorf("load timeout must be positive")
+		}
+		mgr.loadTimeout = d
+		return nil
+	}
+}
+
 // WithSource adds one or more sources to the config manager.
 //
 // The order of the sources is important, as the config will be loaded in the same order.
@@ -31,3 +78,24 @@ func WithSource[C ConfigModel](srcs ...SourceFunc[C]) Option[C] {
 		return nil
 	}
 }
+
+// WithReloadDebounce sets how long to wait after a change is detected by `Watch` before reloading.
+// Any further changes within this interval restart the wait. Defaults to 100ms.
+func WithReloadDebounce[C ConfigModel](d time.Duration) Option[C] {
+	return func(mgr *Config[C]) error {
+		if d < 0 {
+			return fmt.Errorf("reload debounce cannot be negative")
+		}
+		mgr.reloadDebounce = d
+		return nil
+	}
+}
+
+// WithReloadErrorHandler sets a function that is called with the error of every failed reload
+// triggered by `Watch`. The previous config stays in place when a reload fails.
+func WithReloadErrorHandler[C ConfigModel](fn func(error)) Option[C] {
+	return func(mgr *Config[C]) error {
+		mgr.reloadErrorHandler = fn
+		return nil
+	}
+}
@@ -0,0 +1,260 @@
+package ckoanf
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"sort"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	// overrideKey is the key of the override blocks in config documents.
+	overrideKey = "override"
+	// whenKey is the key of the conditions of an override block.
+	whenKey = "when"
+
+	// HostnameAttribute is the attribute override blocks can match the hostname with.
+	// It defaults to the hostname reported by the OS, see `WithAttributes`.
+	HostnameAttribute = "hostname"
+)
+
+// WithAttributes enables override blocks, and sets attributes of the runtime environment that they can match on,
+// such as the region or datacenter the app runs in. Calling it more than once adds to the attributes.
+// The `hostname` attribute is always set, to the hostname reported by the OS unless it is given here.
+//
+// An override block is an `override` array of tables in a config document (embedded defaults, local files,
+// directories and remote config), with a `when` table of conditions. The other keys in the block override
+// the config, but only if all conditions match:
+//
+//	[[override]]
+//	when = { region = "eu-*", hostname = "web-*" }
+//	log_level = "warn"
+//
+// Every condition is a glob pattern (see `path.Match`) or a list of them, of which one must match the attribute.
+// Conditions on attributes that are not set never match.
+//
+// The override blocks of all sources are evaluated after all sources are merged, in the order of the sources,
+// and before the config is interpolated and unmarshalled. They override the values of every source, including
+// the sources that come after the one that defined them. `Config.Report` lists which override blocks applied.
+//
+// Without this option, `override` is a regular key.
+func WithAttributes[C ConfigModel](attrs map[string]string) Option[C] {
+	return func(mgr *Config[C]) error {
+		mgr.overrides = true
+		if mgr.attributes == nil {
+			mgr.attributes = make(map[string]string, len(attrs))
+		}
+		for name, value := range attrs {
+			mgr.attributes[name] = value
+		}
+		return nil
+	}
+}
+
+// overrideBlock is an override block defined by a source, with the values it overrides.
+type overrideBlock struct {
+	report OverrideReport
+	values map[string]interface{}
+}
+
+// extractOverrides removes the override blocks from a parsed config document, and records them for the
+// source currently being loaded. The document is returned as is if override blocks are not enabled.
+func extractOverrides(
+	ctx context.Context, values map[string]interface{}, location string,
+) (map[string]interface{}, error) {
+	state := sourceStateFrom(ctx)
+	raw, ok := values[overrideKey]
+	if !ok || state == nil || !state.overrides {
+		return values, nil
+	}
+
+	blocks, err := parseOverrides(raw, location)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s in %s: %w", overrideKey, location, err)
+	}
+	state.blocks = append(state.blocks, blocks...)
+
+	rest := make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if key != overrideKey {
+			rest[key] = value
+		}
+	}
+	return rest, nil
+}
+
+// parseOverrides parses the override blocks of a config document.
+func parseOverrides(raw interface{}, location string) ([]overrideBlock, error) {
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("must be a list of tables, got %T", raw)
+	}
+
+	blocks := make([]overrideBlock, len(list))
+	for i, elem := range list {
+		values, ok := elem.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("block %d must be a table, got %T", i, elem)
+		}
+		when, err := parseConditions(values[whenKey])
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+
+		block := overrideBlock{
+			report: OverrideReport{Location: location, Index: i, When: when},
+			values: make(map[string]interface{}, len(values)),
+		}
+		for key, value := range values {
+			if key != whenKey {
+				block.values[key] = value
+			}
+		}
+		blocks[i] = block
+	}
+	return blocks, nil
+}
+
+// overrideBlocks returns the override blocks the source recorded, see extractOverrides.
+func (state *sourceState) overrideBlocks(index int, src Source) []overrideBlock {
+	if state.resolved != nil {
+		src.Type = state.resolved.Type
+	}
+
+	blocks := make([]overrideBlock, len(state.blocks))
+	for i, block := range state.blocks {
+		block.report.Source, block.report.Type = index, src.Type
+		blocks[i] = block
+	}
+	return blocks
+}
+
+// parseConditions parses the `when` table of an override block.
+func parseConditions(raw interface{}) (map[string][]string, error) {
+	table, ok := raw.(map[string]interface{})
+	if !ok || len(table) == 0 {
+		return nil, fmt.Errorf("%s must be a table with at least one condition", whenKey)
+	}
+
+	when := make(map[string][]string, len(table))
+	for name, value := range table {
+		var patterns []string
+		switch v := value.(type) {
+		case string:
+			patterns = []string{v}
+		case []interface{}:
+			for _, elem := range v {
+				pattern, ok := elem.(string)
+				if !ok {
+					return nil, fmt.Errorf("condition %s must be a pattern or a list of patterns", name)
+				}
+				patterns = append(patterns, pattern)
+			}
+		default:
+			return nil, fmt.Errorf("condition %s must be a pattern or a list of patterns", name)
+		}
+
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("invalid pattern %s for condition %s: %w", pattern, name, err)
+			}
+		}
+		when[name] = patterns
+	}
+	return when, nil
+}
+
+// matches reports whether all conditions of the block match the attributes.
+func (b overrideBlock) matches(attrs map[string]string) bool {
+	for name, patterns := range b.report.When {
+		value, ok := attrs[name]
+		if !ok || !matchesAny(patterns, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if match, _ := path.Match(pattern, value); match {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideAttributes returns the attributes override blocks are matched against.
+func (mgr *Config[C]) overrideAttributes() map[string]string {
+	attrs := make(map[string]string, len(mgr.attributes)+1)
+	if hostname, err := os.Hostname(); err == nil {
+		attrs[HostnameAttribute] = hostname
+	}
+	for name, value := range mgr.attributes {
+		attrs[name] = value
+	}
+	return attrs
+}
+
+// applyOverrides merges the blocks whose conditions match into the given koanf instance, in order.
+// It returns the origins of the values the applied blocks set, and a report of every block.
+func (mgr *Config[C]) applyOverrides(
+	ctx context.Context, k *koanf.Koanf, blocks []overrideBlock,
+) ([]Origin, []OverrideReport, error) {
+	if len(blocks) == 0 {
+		return nil, nil, nil
+	}
+
+	attrs := mgr.overrideAttributes()
+	var origins []Origin
+	reports := make([]OverrideReport, 0, len(blocks))
+	for _, block := range blocks {
+		report := block.report
+		if !block.matches(attrs) {
+			reports = append(reports, report)
+			continue
+		}
+
+		layer := mgr.newKoanf()
+		if err := layer.Load(mapProvider(block.values), nil); err != nil {
+			return nil, reports, err
+		}
+		values := layer.All()
+
+		blockOrigins := make([]Origin, 0, len(values))
+		for _, key := range sortedKeys(values) {
+			blockOrigins = append(blockOrigins, Origin{
+				Source:   report.Source,
+				Type:     report.Type,
+				Location: report.Location,
+				When:     report.When,
+				Key:      key,
+				Value:    values[key],
+			})
+		}
+		mgr.logOverrides(ctx, k, blockOrigins)
+		if err := k.Merge(layer); err != nil {
+			return nil, reports, fmt.Errorf("failed to apply override block %d of %s: %w", report.Index, report.Location, err)
+		}
+
+		report.Applied, report.Keys = true, len(values)
+		reports = append(reports, report)
+		origins = append(origins, blockOrigins...)
+		mgr.log(ctx, slog.LevelDebug, "applied config override", slog.Any("override", report))
+	}
+	return origins, reports, nil
+}
+
+// conditionNames returns the names of the conditions, sorted.
+func conditionNames(when map[string][]string) []string {
+	names := make([]string, 0, len(when))
+	for name := range when {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,172 @@
+package ckoanf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`key = "base"
+abc = "bas"
+
+[nested]
+foo = "base"
+
+[[override]]
+when = { region = "eu-*" }
+key = "eu"
+
+[[override]]
+when = { region = ["us-*", "eu-west-*"], hostname = "web-*" }
+abc = "web"
+
+[override.nested]
+foo = "web"
+
+[[override]]
+when = { datacenter = "*" }
+key = "dc"
+`), 0o600))
+
+	t.Run("Matching blocks", func(t *testing.T) {
+		cfg, err := Init(&TestModel{},
+			WithAttributes[*TestModel](map[string]string{"region": "eu-west-1", HostnameAttribute: "web-3"}),
+			WithSource(LocalFile[*TestModel](path)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "eu", cfg.Model().Key)
+		assert.Equal(t, "web", cfg.Model().ABC)
+		assert.Equal(t, "web", cfg.Model().Nested.Foo)
+		assert.False(t, cfg.K.Exists("override"))
+
+		overrides := cfg.Report().Overrides
+		require.Len(t, overrides, 3)
+		assert.Equal(t, OverrideReport{
+			Source: 0, Type: SourceTypeLocalFile, Location: path, Index: 0,
+			When: map[string][]string{"region": {"eu-*"}}, Applied: true, Keys: 1,
+		}, overrides[0])
+		assert.True(t, overrides[1].Applied)
+		assert.Equal(t, 2, overrides[1].Keys)
+		// Conditions on attributes that are not set never match
+		assert.False(t, overrides[2].Applied)
+		assert.Equal(t, 0, overrides[2].Keys)
+
+		exp, ok := cfg.Explain("key")
+		require.True(t, ok)
+		assert.Equal(t, []Origin{
+			{Source: 0, Type: SourceTypeLocalFile, Location: path, Line: 1, Key: "key", Value: "base"},
+			{
+				Source: 0, Type: SourceTypeLocalFile, Location: path, When: map[string][]string{"region": {"eu-*"}},
+				Key: "key", Value: "eu",
+			},
+		}, exp.Origins)
+	})
+
+	t.Run("No matching blocks", func(t *testing.T) {
+		cfg, err := Init(&TestModel{},
+			WithAttributes[*TestModel](map[string]string{"region": "us-east-1", HostnameAttribute: "db-1"}),
+			WithSource(LocalFile[*TestModel](path)),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "base", cfg.Model().Key)
+		assert.Equal(t, "bas", cfg.Model().ABC)
+		assert.False(t, cfg.K.Exists("override"))
+		for _, override := range cfg.Report().Overrides {
+			assert.False(t, override.Applied)
+		}
+	})
+
+	t.Run("Override later sources", func(t *testing.T) {
+		t.Setenv("OVERRIDE_TEST__KEY", "from_env")
+		t.Setenv("OVERRIDE_TEST__ABC", "env")
+
+		cfg, err := Init(&TestModel{},
+			WithAttributes[*TestModel](map[string]string{"region": "eu-central-1"}),
+			WithSource(LocalFile[*TestModel](path), Env[*TestModel]("OVERRIDE_TEST__")),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "eu", cfg.Model().Key)
+		assert.Equal(t, "env", cfg.Model().ABC)
+	})
+
+	t.Run("Blocks of every source in order", func(t *testing.T) {
+		cfg, err := Init(&TestModel{},
+			WithAttributes[*TestModel](map[string]string{"region": "eu-west-1", HostnameAttribute: "web-3"}),
+			WithSource(
+				LocalFile[*TestModel](path),
+				EmbeddedDefaults[*TestModel]([]byte("override:\n  - when: {region: eu-west-1}\n    abc: yml\n"), FileTypeYAML),
+			),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "yml", cfg.Model().ABC)
+		require.Len(t, cfg.Report().Overrides, 4)
+		assert.Equal(t, 1, cfg.Report().Overrides[3].Source)
+	})
+
+	t.Run("Hostname", func(t *testing.T) {
+		hostname, err := os.Hostname()
+		require.NoError(t, err)
+
+		cfg, err := Init(&TestModel{}, WithAttributes[*TestModel](nil), WithSource(EmbeddedDefaults[*TestModel](
+			[]byte(`{"key": "base", "abc": "bas", "override": [{"when": {"hostname": "`+hostname+`"}, "key": "host"}]}`),
+			FileTypeJSON,
+		)))
+		require.NoError(t, err)
+		assert.Equal(t, "host", cfg.Model().Key)
+	})
+
+	t.Run("Invalid blocks", func(t *testing.T) {
+		for name, doc := range map[string]string{
+			"Not a list":        "key = 'a'\n[override]\nkey = 'b'",
+			"No conditions":     "[[override]]\nkey = 'b'",
+			"Invalid pattern":   "[[override]]\nwhen = { region = '[' }\nkey = 'b'",
+			"Invalid condition": "[[override]]\nwhen = { region = 1 }\nkey = 'b'",
+		} {
+			t.Run(name, func(t *testing.T) {
+				_, err := Init(&TestModel{}, WithAttributes[*TestModel](nil),
+					WithSource(EmbeddedDefaults[*TestModel]([]byte(doc), FileTypeTOML)))
+				var loadErr *SourceLoadError
+				assert.ErrorAs(t, err, &loadErr)
+			})
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](path)))
+		require.NoError(t, err)
+		assert.Equal(t, "base", cfg.Model().Key)
+		assert.True(t, cfg.K.Exists("override"))
+		assert.Empty(t, cfg.Report().Overrides)
+
+		// Without override blocks, override is a regular key
+		doc := "override = true\nkey = 'k'\nabc = 'abc'"
+		cfg, err = Init(&TestModel{}, WithSource(EmbeddedDefaults[*TestModel]([]byte(doc), FileTypeTOML)))
+		require.NoError(t, err)
+		assert.Equal(t, true, cfg.K.Get("override"))
+	})
+
+	t.Run("Env", func(t *testing.T) {
+		t.Setenv("OVERRIDE_TEST__OVERRIDE", "1")
+		t.Setenv("OVERRIDE_TEST__ABC", "env")
+
+		// Override blocks are only read from config documents
+		cfg, err := Init(&TestModel{},
+			WithAttributes[*TestModel](map[string]string{"region": "eu-central-1"}),
+			WithSource(LocalFile[*TestModel](path), Env[*TestModel]("OVERRIDE_TEST__")),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "eu", cfg.Model().Key)
+		assert.Equal(t, "1", cfg.K.Get("override"))
+
+		exp, ok := cfg.Explain("override")
+		require.True(t, ok)
+		assert.Equal(t, []Origin{
+			{Source: 1, Type: SourceTypeEnv, Location: "OVERRIDE_TEST__OVERRIDE", Key: "override", Value: "1"},
+		}, exp.Origins)
+	})
+}
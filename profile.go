@@ -0,0 +1,151 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/maps"
+	"github.com/knadh/koanf/v2"
+)
+
+// profileKey is the key of the profile sections in config documents.
+const profileKey = "profile"
+
+// ErrUnknownProfile matches errors of a selected profile that no source defines.
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// UnknownProfileError is returned by `Load` when the active profile is not defined by any source.
+// It matches `ErrUnknownProfile` with `errors.Is`.
+type UnknownProfileError struct {
+	Profile string
+	// Known lists the profiles the sources do define.
+	Known []string
+}
+
+func (e *UnknownProfileError) Error() string {
+	if len(e.Known) == 0 {
+		return fmt.Sprintf("unknown profile %s, no profiles are defined", e.Profile)
+	}
+	return fmt.Sprintf("unknown profile %s, defined profiles are: %s", e.Profile, strings.Join(e.Known, ", "))
+}
+
+func (e *UnknownProfileError) Is(target error) bool {
+	return target == ErrUnknownProfile //nolint:errorlint // Sentinel comparison.
+}
+
+// WithProfile sets the active profile, such as "dev", "staging" or "prod", usually taken from a flag or
+// an environment variable. An empty profile means no profile is active, which is the default.
+//
+// When a profile is active:
+//   - `LocalFile` (and `SearchFile`, `SearchFiles` and `FindUp`) also load the profile file next to every file
+//     they load if it exists, on top of it. For `config.toml` and profile prod that is `config.prod.toml`.
+//   - In every config document, the `[profile.prod]` section overrides the other values of that document.
+//     As a section only overrides the values of its own document, the sources after it still override the section.
+//
+// With this option, the `profile` sections themselves are always left out of the config, also when the profile
+// is empty. Without it, `profile` is a regular key.
+// Values from profile files and sections list the profile in their origin, see `Config.Explain`.
+// If no source defines the profile, in a profile file or a profile section, `Load` fails with an
+// `UnknownProfileError`.
+func WithProfile[C ConfigModel](profile string) Option[C] {
+	return func(mgr *Config[C]) error {
+		if strings.ContainsAny(profile, `./\`) {
+			return fmt.Errorf("invalid profile name: %q", profile)
+		}
+		mgr.profilesEnabled, mgr.profile = true, profile
+		return nil
+	}
+}
+
+// activeProfile returns the active profile of the source currently being loaded.
+func activeProfile(ctx context.Context) string {
+	if state := sourceStateFrom(ctx); state != nil {
+		return state.profile
+	}
+	return ""
+}
+
+// markProfiles records that the source currently being loaded defines the given profiles.
+func markProfiles(ctx context.Context, profiles ...string) {
+	if state := sourceStateFrom(ctx); state != nil {
+		state.profiles = append(state.profiles, profiles...)
+	}
+}
+
+// splitProfiles removes the profile sections from the parsed document, and returns the section of the
+// given profile separately (if it is not empty), with its lines. It also returns the names of all profiles
+// in the document.
+func splitProfiles(values map[string]interface{}, lines map[string]int, profile string) (
+	base, section map[string]interface{}, sectionLines map[string]int, defined []string,
+) {
+	profiles, ok := values[profileKey].(map[string]interface{})
+	if !ok {
+		return values, nil, nil, nil
+	}
+
+	base = make(map[string]interface{}, len(values))
+	for key, value := range values {
+		if key != profileKey {
+			base[key] = value
+		}
+	}
+	for name := range profiles {
+		defined = append(defined, name)
+	}
+
+	section, ok = profiles[profile].(map[string]interface{})
+	if !ok || profile == "" {
+		return base, nil, nil, defined
+	}
+	section = maps.Copy(section)
+
+	prefix := profileKey + defaultDelimiter + profile + defaultDelimiter
+	sectionLines = make(map[string]int)
+	for key, line := range lines {
+		if rest, ok := strings.CutPrefix(key, prefix); ok {
+			sectionLines[rest] = line
+		}
+	}
+	return base, section, sectionLines, defined
+}
+
+// profilePath returns the path of the profile file of the given file, `config.prod.toml` for `config.toml`.
+func profilePath(path, profile string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + profile + ext
+}
+
+// loadLocalFile loads the file, and if a profile is active and the profile file of it exists, that file on top.
+// A missing file matches `ErrNotFound`.
+func loadLocalFile(ctx context.Context, k *koanf.Koanf, path string) error {
+	if err := loadLocalFiles(ctx, k, []string{path}); err != nil {
+		return err
+	}
+
+	profile := activeProfile(ctx)
+	if profile == "" {
+		return nil
+	}
+	path = profilePath(path, profile)
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	markProfiles(ctx, profile)
+	return loadFile(ctx, k, path, profile, nil)
+}
+
+// checkProfile fails if a profile is active, but none of the sources defined it.
+func (mgr *Config[C]) checkProfile(defined []string) error {
+	if mgr.profile == "" || slices.Contains(defined, mgr.profile) {
+		return nil
+	}
+
+	slices.Sort(defined)
+	return &UnknownProfileError{Profile: mgr.profile, Known: slices.Compact(defined)}
+}
@@ -0,0 +1,107 @@
+package ckoanf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`key = "base"
+abc = "bas"
+
+[nested]
+foo = "base"
+
+[profile.prod]
+key = "prod_section"
+
+[profile.prod.nested]
+foo = "prod_section"
+
+[profile.dev]
+key = "dev_section"
+`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.staging.toml"), []byte("abc = 'stg'"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.prod.toml"), []byte("abc = 'prd'"), 0o600))
+
+	t.Run("Sections and files", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithProfile[*TestModel]("prod"), WithSource(LocalFile[*TestModel](path)))
+		require.NoError(t, err)
+		assert.Equal(t, "prod_section", cfg.Model().Key)
+		assert.Equal(t, "prd", cfg.Model().ABC)
+		assert.Equal(t, "prod_section", cfg.Model().Nested.Foo)
+		assert.False(t, cfg.K.Exists("profile"))
+
+		exp, ok := cfg.Explain("key")
+		require.True(t, ok)
+		assert.Equal(t, []Origin{
+			{Type: SourceTypeLocalFile, Location: path, Line: 1, Key: "key", Value: "base"},
+			{Type: SourceTypeLocalFile, Location: path, Line: 8, Profile: "prod", Key: "key", Value: "prod_section"},
+		}, exp.Origins)
+
+		exp, ok = cfg.Explain("abc")
+		require.True(t, ok)
+		assert.Equal(t, Origin{
+			Type: SourceTypeLocalFile, Location: filepath.Join(dir, "config.prod.toml"), Line: 1,
+			Profile: "prod", Key: "abc", Value: "prd",
+		}, exp.Origins[1])
+	})
+
+	t.Run("Only a profile file", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithProfile[*TestModel]("staging"), WithSource(LocalFile[*TestModel](path)))
+		require.NoError(t, err)
+		assert.Equal(t, "base", cfg.Model().Key)
+		assert.Equal(t, "stg", cfg.Model().ABC)
+	})
+
+	t.Run("Embedded sections", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithProfile[*TestModel]("dev"), WithSource(
+			EmbeddedDefaults[*TestModel]([]byte("key = 'default'\n[profile.dev]\nabc = 'dev'"), FileTypeTOML),
+			LocalFile[*TestModel](path),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, "dev_section", cfg.Model().Key)
+		// Sections only override the values of their own document, later sources still take precedence
+		assert.Equal(t, "bas", cfg.Model().ABC)
+	})
+
+	t.Run("No profile", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithProfile[*TestModel](""), WithSource(LocalFile[*TestModel](path)))
+		require.NoError(t, err)
+		assert.Equal(t, "base", cfg.Model().Key)
+		assert.Equal(t, "bas", cfg.Model().ABC)
+		assert.False(t, cfg.K.Exists("profile"))
+
+		out, err := cfg.Marshal(FileTypeTOML, MarshalOptions{})
+		require.NoError(t, err)
+		assert.NotContains(t, string(out), "profile")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		// Without profiles, profile is a regular key
+		cfg, err := Init(&TestModel{}, WithSource(LocalFile[*TestModel](path)))
+		require.NoError(t, err)
+		assert.Equal(t, "base", cfg.Model().Key)
+		assert.Equal(t, "prod_section", cfg.K.String("profile.prod.key"))
+	})
+
+	t.Run("Unknown profile", func(t *testing.T) {
+		_, err := Init(&TestModel{}, WithProfile[*TestModel]("qa"), WithSource(LocalFile[*TestModel](path)))
+		require.ErrorIs(t, err, ErrUnknownProfile)
+		assert.EqualError(t, err, "failed to load config: unknown profile qa, defined profiles are: dev, prod")
+
+		_, err = Init(&TestModel{}, WithProfile[*TestModel]("qa"))
+		assert.EqualError(t, err, "failed to load config: unknown profile qa, no profiles are defined")
+	})
+
+	t.Run("Invalid profile", func(t *testing.T) {
+		_, err := New(&TestModel{}, WithProfile[*TestModel]("../prod"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,92 @@
+package ckoanf
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// Origin describes a value that was set by a source.
+type Origin struct {
+	// Index of the source in the order the sources were added, -1 for values changed with `Set`.
+	Source int
+	Type   SourceType
+	// Location the value was read from, such as a file path, environment variable or flag name.
+	// Empty if the source does not have a location.
+	Location string
+
+	// Line in the file the value was defined on, 0 if unknown.
+	Line int
+	// Profile is the active profile, if the value came from a profile section or profile file (see `WithProfile`).
+	Profile string
+	// When are the conditions of the override block the value came from, if it did (see `WithAttributes`).
+	When map[string][]string
+
+	// Key path of the value, for example `nested.foo`.
+	Key string
+	// Value as it was set by the source, before being overridden by later sources.
+	Value interface{}
+}
+
+// Explanation describes how the final value of a key came to be.
+type Explanation struct {
+	Key   string
+	Value interface{}
+
+	// Origins lists every source that set the key, or a key nested below it, in the order
+	// the sources were merged. For a single value, the last origin is the one that is used.
+	Origins []Origin
+}
+
+// Explain returns the final value of the given key path in the current config, together with the
+// ordered list of sources that set or overrode it. It returns false if the key does not exist.
+// The values of secrets are redacted.
+func (mgr *Config[C]) Explain(key string) (Explanation, bool) {
+	snap := mgr.current.Load()
+	if !snap.k.Exists(key) {
+		return Explanation{}, false
+	}
+
+	exp := Explanation{Key: key, Value: mgr.redactTree(key, snap.k.Get(key))}
+	for _, origin := range snap.origins {
+		if origin.Key == key || strings.HasPrefix(origin.Key, key+defaultDelimiter) {
+			origin.Value = mgr.redactTree(origin.Key, origin.Value)
+			exp.Origins = append(exp.Origins, origin)
+		}
+	}
+	return exp, true
+}
+
+// sourceOrigins returns the origins of the values a source loaded into the given layer.
+func (state *sourceState) sourceOrigins(index int, src Source, layer *koanf.Koanf) []Origin {
+	layers := state.origins
+	if len(layers) == 0 {
+		layers = []layerOrigin{{location: src.Location, values: layer.All()}}
+	}
+
+	var origins []Origin
+	for _, l := range layers {
+		for _, key := range sortedKeys(l.values) {
+			origins = append(origins, Origin{
+				Source:   index,
+				Type:     src.Type,
+				Location: l.location,
+				Line:     l.lines[key],
+				Profile:  l.profile,
+				Key:      key,
+				Value:    l.values[key],
+			})
+		}
+	}
+	return origins
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
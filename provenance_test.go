@@ -0,0 +1,67 @@
+package ckoanf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplain(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	require.NoError(t, os.WriteFile(path, []byte("key = 'from_file'\nabc = 'fil'"), 0o600))
+
+	t.Setenv("EXPLAIN_TEST__KEY", "from_env")
+	t.Setenv("EXPLAIN_TEST__NESTED__FOO", "env_foo")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.String("key", "", "")
+	flags.String("abc", "flg", "")
+	require.NoError(t, flags.Parse([]string{"--key", "from_flag"}))
+
+	cfg, err := Init(&TestModel{}, WithSource(
+		EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML),
+		LocalFile[*TestModel](path),
+		Env[*TestModel]("EXPLAIN_TEST__"),
+		PFlags[*TestModel](flags),
+	))
+	require.NoError(t, err)
+
+	exp, ok := cfg.Explain("key")
+	require.True(t, ok)
+	assert.Equal(t, "from_flag", exp.Value)
+	assert.Equal(t, []Origin{
+		{Source: 0, Type: SourceTypeDefault, Location: "embedded toml", Line: 2, Key: "key", Value: "value"},
+		{Source: 1, Type: SourceTypeLocalFile, Location: path, Line: 1, Key: "key", Value: "from_file"},
+		{Source: 2, Type: SourceTypeEnv, Location: "EXPLAIN_TEST__KEY", Key: "key", Value: "from_env"},
+		{Source: 3, Type: SourceTypePFlag, Location: "--key", Key: "key", Value: "from_flag"},
+	}, exp.Origins)
+
+	// The unchanged flag default does not override the value from the file
+	exp, ok = cfg.Explain("abc")
+	require.True(t, ok)
+	assert.Equal(t, "fil", exp.Value)
+	assert.Len(t, exp.Origins, 2)
+
+	// Parent keys explain all values below them
+	exp, ok = cfg.Explain("nested")
+	require.True(t, ok)
+	assert.Equal(t, map[string]interface{}{"foo": "env_foo"}, exp.Value)
+	assert.Equal(t, []Origin{
+		{Source: 0, Type: SourceTypeDefault, Location: "embedded toml", Line: 7, Key: "nested.foo", Value: "bar"},
+		{Source: 2, Type: SourceTypeEnv, Location: "EXPLAIN_TEST__NESTED__FOO", Key: "nested.foo", Value: "env_foo"},
+	}, exp.Origins)
+
+	_, ok = cfg.Explain("does.not.exist")
+	assert.False(t, ok)
+
+	require.NoError(t, cfg.Set("key", "from_set"))
+	exp, ok = cfg.Explain("key")
+	require.True(t, ok)
+	assert.Equal(t, "from_set", exp.Value)
+	assert.Len(t, exp.Origins, 5)
+	assert.Equal(t, Origin{Source: -1, Type: SourceTypeSet, Key: "key", Value: "from_set"}, exp.Origins[4])
+}
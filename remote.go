@@ -0,0 +1,217 @@
+package ckoanf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// RemoteOptions configures a `Remote` source.
+type RemoteOptions struct {
+	// Client is used to make the requests, defaults to `http.DefaultClient`.
+	// Requests are cancelled when the load times out regardless of the client's timeout.
+	Client *http.Client
+
+	// Headers are added to every request.
+	Headers http.Header
+	// BearerToken, if set, is sent in the `Authorization` header.
+	BearerToken string
+
+	// FileType of the document. If empty, it is taken from the `Content-Type` of the response,
+	// or if that is not a known type, inferred from the extension of the URL path.
+	FileType ConfigFileType
+
+	// PollInterval, if set, makes the source watchable (see `Config.Watch`): the document is requested
+	// again every interval, and the config is reloaded when it changed. Failed polls are ignored.
+	PollInterval time.Duration
+}
+
+// Remote is a source that loads the config from a YAML, TOML or JSON document over HTTP(S).
+//
+// The ETag of the document is stored, and sent as `If-None-Match` in later requests, so that an
+// unchanged document is not transferred again. A 404 response matches `ErrNotFound`.
+func Remote[C ConfigModel](rawURL string, opts RemoteOptions) SourceFunc[C] {
+	return func(mgr *Config[C]) (Source, error) {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return Source{}, fmt.Errorf("invalid remote config url: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return Source{}, fmt.Errorf("invalid remote config url %s: scheme must be http or https", u.Redacted())
+		}
+		if opts.FileType != "" {
+			if err := opts.FileType.Valid(); err != nil {
+				return Source{}, err
+			}
+		}
+		if opts.PollInterval < 0 {
+			return Source{}, fmt.Errorf("poll interval cannot be negative")
+		}
+
+		r := &remote{url: rawURL, location: u.Redacted(), opts: opts}
+		src := Source{
+			Type:     SourceTypeRemote,
+			Location: r.location,
+			Load: func(ctx context.Context, k *koanf.Koanf) error {
+				doc, err := r.fetch(ctx)
+				if err == nil {
+					err = loadDocument(ctx, k, doc.body, doc.filetype, r.location)
+				}
+				if err != nil {
+					return fmt.Errorf("failed to load config from remote: %w", err)
+				}
+				return nil
+			},
+		}
+		if opts.PollInterval > 0 {
+			src.Watch = func(ctx context.Context, notify func()) error {
+				go r.poll(ctx, notify)
+				return nil
+			}
+		}
+		return src, nil
+	}
+}
+
+// remote holds the state of a `Remote` source between loads.
+type remote struct {
+	url      string
+	location string
+	opts     RemoteOptions
+
+	mu sync.Mutex
+	// The last document that was fetched, nil if none was.
+	last *remoteDocument
+}
+
+type remoteDocument struct {
+	body     []byte
+	etag     string
+	filetype ConfigFileType
+}
+
+// fetch requests the document, or returns the last one if it did not change.
+func (r *remote) fetch(ctx context.Context) (*remoteDocument, error) {
+	r.mu.Lock()
+	last := r.last
+	r.mu.Unlock()
+
+	doc, err := r.request(ctx, last)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.last = doc
+	r.mu.Unlock()
+	return doc, nil
+}
+
+// request requests the document, sending the ETag of the given previous document if any.
+// It returns the previous document if the server responds that it is unchanged.
+func (r *remote) request(ctx context.Context, prev *remoteDocument) (*remoteDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range r.opts.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	if r.opts.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.opts.BearerToken)
+	}
+	if prev != nil && prev.etag != "" {
+		req.Header.Set("If-None-Match", prev.etag)
+	}
+
+	client := r.opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request %s: %w", r.location, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && prev != nil:
+		return prev, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, &NotFoundError{Locations: []string{r.location}, Err: fmt.Errorf("unexpected status %s", resp.Status)}
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("failed to request %s: unexpected status %s", r.location, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from %s: %w", r.location, err)
+	}
+	return &remoteDocument{
+		body:     body,
+		etag:     resp.Header.Get("ETag"),
+		filetype: r.filetype(resp.Header.Get("Content-Type")),
+	}, nil
+}
+
+// filetype returns the configured file type, the one of the content type, or the one of the URL extension.
+func (r *remote) filetype(contentType string) ConfigFileType {
+	if r.opts.FileType != "" {
+		return r.opts.FileType
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/json":
+		return FileTypeJSON
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return FileTypeYAML
+	case "application/toml", "text/toml", "text/x-toml":
+		return FileTypeTOML
+	}
+
+	path := r.url
+	if u, err := url.Parse(r.url); err == nil {
+		path = u.Path
+	}
+	return inferConfigFiletype(path)
+}
+
+// poll requests the document every interval until the context is done, and notifies when it changed
+// since the last load.
+func (r *remote) poll(ctx context.Context, notify func()) {
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.mu.Lock()
+		last := r.last
+		r.mu.Unlock()
+
+		reqCtx, cancel := context.WithTimeout(ctx, r.opts.PollInterval)
+		doc, err := r.request(reqCtx, last)
+		cancel()
+		if err != nil {
+			continue
+		}
+		if last == nil || !bytes.Equal(doc.body, last.body) || doc.filetype != last.filetype {
+			notify()
+		}
+	}
+}
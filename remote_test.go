@@ -0,0 +1,170 @@
+package ckoanf
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// remoteServer serves a config document with an ETag, and records the requests it gets.
+type remoteServer struct {
+	mu          sync.Mutex
+	body        string
+	contentType string
+	etag        string
+
+	requests    atomic.Int32
+	notModified atomic.Int32
+	lastHeaders http.Header
+}
+
+func (s *remoteServer) set(body, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body, s.etag = body, etag
+}
+
+func (s *remoteServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastHeaders = r.Header.Clone()
+
+	if r.Header.Get("If-None-Match") == s.etag {
+		s.notModified.Add(1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if s.contentType != "" {
+		w.Header().Set("Content-Type", s.contentType)
+	}
+	w.Header().Set("ETag", s.etag)
+	_, _ = w.Write([]byte(s.body))
+}
+
+func TestRemote(t *testing.T) {
+	t.Run("Content-Type and ETag", func(t *testing.T) {
+		srv := &remoteServer{body: `{"key": "from_remote"}`, contentType: "application/json; charset=utf-8", etag: `"v1"`}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+
+		headers := http.Header{}
+		headers.Set("X-Custom", "custom")
+		cfg, err := Init(&TestModel{}, WithSource(Remote[*TestModel](server.URL+"/config", RemoteOptions{
+			Headers:     headers,
+			BearerToken: "token",
+		})))
+		require.NoError(t, err)
+		assert.Equal(t, "from_remote", cfg.Model().Key)
+		assert.Equal(t, "custom", srv.lastHeaders.Get("X-Custom"))
+		assert.Equal(t, "Bearer token", srv.lastHeaders.Get("Authorization"))
+		assert.Empty(t, srv.lastHeaders.Get("If-None-Match"))
+
+		// Unchanged, the previous document is reused
+		require.NoError(t, cfg.Load(context.Background()))
+		assert.Equal(t, `"v1"`, srv.lastHeaders.Get("If-None-Match"))
+		assert.Equal(t, int32(1), srv.notModified.Load())
+		assert.Equal(t, "from_remote", cfg.Model().Key)
+
+		srv.set(`{"key": "changed"}`, `"v2"`)
+		require.NoError(t, cfg.Load(context.Background()))
+		assert.Equal(t, "changed", cfg.Model().Key)
+	})
+
+	t.Run("URL extension", func(t *testing.T) {
+		server := httptest.NewServer(&remoteServer{body: "key: from_yaml", etag: `"v1"`})
+		defer server.Close()
+
+		cfg, err := Init(&TestModel{}, WithSource(Remote[*TestModel](server.URL+"/config.yaml?env=prod", RemoteOptions{})))
+		require.NoError(t, err)
+		assert.Equal(t, "from_yaml", cfg.Model().Key)
+
+		exp, ok := cfg.Explain("key")
+		require.True(t, ok)
+		assert.Equal(t, SourceTypeRemote, exp.Origins[0].Type)
+		assert.Equal(t, 1, exp.Origins[0].Line)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		_, err := Init(&TestModel{}, WithSource(Remote[*TestModel](server.URL+"/config.toml", RemoteOptions{})))
+		assert.ErrorIs(t, err, ErrNotFound)
+
+		cfg, err := Init(&TestModel{}, WithSource(
+			EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML),
+			OptionalSource(Remote[*TestModel](server.URL+"/config.toml", RemoteOptions{}), ErrNotFound),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, "value", cfg.Model().Key)
+	})
+
+	t.Run("Server error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		_, err := Init(&TestModel{}, WithSource(Remote[*TestModel](server.URL+"/config.toml", RemoteOptions{})))
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrNotFound)
+		assert.Contains(t, err.Error(), "500 Internal Server Error")
+	})
+
+	t.Run("Load context deadline", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+		defer server.Close()
+
+		cfg, err := New(&TestModel{}, WithSource(Remote[*TestModel](server.URL+"/config.toml", RemoteOptions{})))
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, cfg.Load(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("Polling", func(t *testing.T) {
+		srv := &remoteServer{body: "key = 'old'", etag: `"v1"`}
+		server := httptest.NewServer(srv)
+		defer server.Close()
+
+		cfg, err := Init(&TestModel{},
+			WithSource(Remote[*TestModel](server.URL+"/config.toml", RemoteOptions{PollInterval: 10 * time.Millisecond})),
+			WithReloadDebounce[*TestModel](10*time.Millisecond),
+		)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		require.NoError(t, cfg.Watch(ctx))
+
+		srv.set("key = 'new'", `"v2"`)
+		assert.Eventually(t, keyIsNew(cfg), watchTimeout, 10*time.Millisecond)
+	})
+
+	t.Run("Invalid options", func(t *testing.T) {
+		for _, tc := range []struct {
+			url  string
+			opts RemoteOptions
+		}{
+			{url: "ftp://example.com/config.toml"},
+			{url: "://"},
+			{url: "https://example.com/config", opts: RemoteOptions{FileType: "ini"}},
+			{url: "https://example.com/config", opts: RemoteOptions{PollInterval: -time.Second}},
+		} {
+			_, err := New(&TestModel{}, WithSource(Remote[*TestModel](tc.url, tc.opts)))
+			assert.Error(t, err, tc.url)
+		}
+	})
+}
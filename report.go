@@ -0,0 +1,154 @@
+package ckoanf
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SourceStatus is the outcome of loading a single source.
+type SourceStatus string
+
+const (
+	SourceStatusLoaded  SourceStatus = "loaded"
+	SourceStatusSkipped SourceStatus = "skipped"
+	SourceStatusFailed  SourceStatus = "failed"
+	// SourceStatusCached means the source failed, and its last-known-good values were loaded from
+	// its cache instead, see `Cached`.
+	SourceStatusCached SourceStatus = "cached"
+)
+
+// SourceReport describes what happened to a single source during a load.
+type SourceReport struct {
+	// Index of the source in the order the sources were added.
+	Index    int
+	Type     SourceType
+	Location string
+
+	Status SourceStatus
+	// Err is the reason the source was skipped, failed or fell back to its cache.
+	Err error
+
+	Duration time.Duration
+	// Number of values (leaf keys) the source contributed.
+	Keys int
+}
+
+// LogValue implements slog.LogValuer.
+func (r SourceReport) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Int("index", r.Index),
+		slog.String("type", r.Type.String()),
+		slog.String("location", r.Location),
+		slog.String("status", string(r.Status)),
+		slog.Duration("duration", r.Duration),
+		slog.Int("keys", r.Keys),
+	}
+	if r.Err != nil {
+		attrs = append(attrs, slog.String("error", r.Err.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// OverrideReport describes an override block of a source and whether it applied, see `WithAttributes`.
+type OverrideReport struct {
+	// Source is the index of the source that defined the block.
+	Source   int
+	Type     SourceType
+	Location string
+	// Index of the block in the source.
+	Index int
+
+	// When are the conditions of the block, by attribute.
+	When map[string][]string
+	// Applied reports whether all conditions matched.
+	Applied bool
+	// Number of values (leaf keys) the block set, if it applied.
+	Keys int
+}
+
+// LogValue implements slog.LogValuer.
+func (r OverrideReport) LogValue() slog.Value {
+	when := make([]slog.Attr, 0, len(r.When))
+	for _, name := range conditionNames(r.When) {
+		when = append(when, slog.String(name, strings.Join(r.When[name], ", ")))
+	}
+
+	return slog.GroupValue(
+		slog.Int("source", r.Source),
+		slog.String("type", r.Type.String()),
+		slog.String("location", r.Location),
+		slog.Int("index", r.Index),
+		slog.Attr{Key: "when", Value: slog.GroupValue(when...)},
+		slog.Bool("applied", r.Applied),
+		slog.Int("keys", r.Keys),
+	)
+}
+
+// LoadReport describes what happened during a load, see `Config.Report`.
+type LoadReport struct {
+	Start    time.Time
+	Duration time.Duration
+
+	// Sources lists the sources in the order they were loaded. In fail-fast mode the sources
+	// after a failed one are not attempted and not listed.
+	Sources []SourceReport
+	// Overrides lists the override blocks of the loaded sources in the order they were evaluated,
+	// and whether they applied.
+	Overrides []OverrideReport
+
+	// Err is the error the load returned, nil if it succeeded.
+	Err error
+}
+
+// LogValue implements slog.LogValuer.
+func (r LoadReport) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Time("start", r.Start),
+		slog.Duration("duration", r.Duration),
+	}
+	if r.Err != nil {
+		attrs = append(attrs, slog.String("error", r.Err.Error()))
+	}
+
+	sources := make([]slog.Attr, len(r.Sources))
+	for i, src := range r.Sources {
+		sources[i] = slog.Any(strconv.Itoa(i), src)
+	}
+	attrs = append(attrs, slog.Attr{Key: "sources", Value: slog.GroupValue(sources...)})
+
+	if len(r.Overrides) > 0 {
+		overrides := make([]slog.Attr, len(r.Overrides))
+		for i, override := range r.Overrides {
+			overrides[i] = slog.Any(strconv.Itoa(i), override)
+		}
+		attrs = append(attrs, slog.Attr{Key: "overrides", Value: slog.GroupValue(overrides...)})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// Report returns the report of the last load, whether it succeeded or not.
+// It returns the zero report if the config has not been loaded yet.
+func (mgr *Config[C]) Report() LoadReport {
+	if report := mgr.report.Load(); report != nil {
+		return *report
+	}
+	return LoadReport{}
+}
+
+// markSkipped records that the source currently being loaded was skipped because of the given error.
+func markSkipped(ctx context.Context, err error) {
+	if state := sourceStateFrom(ctx); state != nil {
+		state.skipped = err
+	}
+}
+
+// markFallback records that the source currently being loaded fell back to its cache because of the given error.
+func markFallback(ctx context.Context, err error) {
+	if state := sourceStateFrom(ctx); state != nil {
+		state.fallback = err
+	}
+}
@@ -0,0 +1,80 @@
+package ckoanf
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.toml")
+
+	cfg, err := New(&TestModel{}, WithSource(
+		EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML),
+		OptionalSource(LocalFile[*TestModel](missing), ErrNotFound),
+	))
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Report().Sources)
+
+	require.NoError(t, cfg.Load())
+
+	report := cfg.Report()
+	assert.NoError(t, report.Err)
+	assert.False(t, report.Start.IsZero())
+	require.Len(t, report.Sources, 2)
+
+	defaults := report.Sources[0]
+	assert.Equal(t, SourceTypeDefault, defaults.Type)
+	assert.Equal(t, SourceStatusLoaded, defaults.Status)
+	assert.Equal(t, 4, defaults.Keys)
+	assert.NoError(t, defaults.Err)
+
+	file := report.Sources[1]
+	assert.Equal(t, 1, file.Index)
+	assert.Equal(t, SourceTypeLocalFile, file.Type)
+	assert.Equal(t, missing, file.Location)
+	assert.Equal(t, SourceStatusSkipped, file.Status)
+	assert.ErrorIs(t, file.Err, ErrNotFound)
+	assert.Equal(t, 0, file.Keys)
+
+	t.Run("Failed load", func(t *testing.T) {
+		cfg, err := New(&TestModel{}, WithSource(
+			EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML),
+			LocalFile[*TestModel](missing),
+		))
+		require.NoError(t, err)
+		require.Error(t, cfg.Load())
+
+		report := cfg.Report()
+		assert.ErrorIs(t, report.Err, ErrNotFound)
+		require.Len(t, report.Sources, 2)
+		assert.Equal(t, SourceStatusFailed, report.Sources[1].Status)
+	})
+
+	t.Run("slog", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewJSONHandler(&buf, nil))
+		logger.Info("loaded config", "report", report)
+
+		var out struct {
+			Report struct {
+				Sources map[string]struct {
+					Type   string `json:"type"`
+					Status string `json:"status"`
+					Keys   int    `json:"keys"`
+					Error  string `json:"error"`
+				} `json:"sources"`
+			} `json:"report"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		assert.Equal(t, "default", out.Report.Sources["0"].Type)
+		assert.Equal(t, 4, out.Report.Sources["0"].Keys)
+		assert.Equal(t, "skipped", out.Report.Sources["1"].Status)
+		assert.Contains(t, out.Report.Sources["1"].Error, "config not found")
+	})
+}
@@ -0,0 +1,97 @@
+package ckoanf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Resolver resolves references of the form `${scheme:argument}` in config values, such as secrets that
+// are kept in separate files or a secret store. See `WithResolver`.
+type Resolver interface {
+	// Resolve returns the value the argument refers to. The context is done when the load times out.
+	// Errors that match `ErrNotFound` make the reference fall back to its default, if it has one.
+	Resolve(ctx context.Context, arg string) (string, error)
+}
+
+// ResolverFunc is a function that implements `Resolver`.
+type ResolverFunc func(ctx context.Context, arg string) (string, error)
+
+// Resolve calls the function.
+func (f ResolverFunc) Resolve(ctx context.Context, arg string) (string, error) {
+	return f(ctx, arg)
+}
+
+// WithResolver registers a resolver for references with the given scheme, for example
+// `WithResolver("file", FileResolver())` resolves `${file:/run/secrets/db_password}`.
+//
+// Registering a resolver enables interpolation (see `WithInterpolation`). Values that use a resolver,
+// or that refer to a value that does, are marked secret and redacted (see `IsSecret`).
+// Resolvers are called during `Load`, with a context that is done when the load times out.
+func WithResolver[C ConfigModel](scheme string, resolver Resolver) Option[C] {
+	return func(mgr *Config[C]) error {
+		if scheme == "" || scheme == "env" || strings.ContainsAny(scheme, ":${}") {
+			return fmt.Errorf("invalid resolver scheme: %q", scheme)
+		}
+		if resolver == nil {
+			return fmt.Errorf("resolver for scheme %s is nil", scheme)
+		}
+
+		if mgr.resolvers == nil {
+			mgr.resolvers = make(map[string]Resolver)
+		}
+		mgr.resolvers[scheme] = resolver
+		mgr.interpolation = true
+		return nil
+	}
+}
+
+// FileResolver returns a resolver that reads the value from the file at the given path, with
+// trailing newlines removed. This is how secrets are usually mounted, for example by Docker or Kubernetes.
+//
+//nolint:ireturn,nolintlint // Returns the interface on purpose.
+func FileResolver() Resolver {
+	return ResolverFunc(func(_ context.Context, path string) (string, error) {
+		b, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", &NotFoundError{Locations: []string{path}, Err: err}
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+		return strings.TrimRight(string(b), "\r\n"), nil
+	})
+}
+
+// ExecResolver returns a resolver that runs the given command and uses its output, with trailing newlines
+// removed. The command is split on whitespace and not run in a shell, for example `${exec:pass show db}`.
+// The command is killed when the load times out.
+//
+// Only use it with config from trusted sources, as it allows the config to run arbitrary commands.
+//
+//nolint:ireturn,nolintlint // Returns the interface on purpose.
+func ExecResolver() Resolver {
+	return ResolverFunc(func(ctx context.Context, command string) (string, error) {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return "", fmt.Errorf("empty command")
+		}
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...) //nolint:gosec // Running the command is the point.
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != "" {
+				return "", fmt.Errorf("failed to run %s: %w: %s", args[0], err, msg)
+			}
+			return "", fmt.Errorf("failed to run %s: %w", args[0], err)
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	})
+}
@@ -0,0 +1,175 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+)
+
+const (
+	defaultRetryAttempts       = 3
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 10 * time.Second
+	defaultRetryMultiplier     = 2
+)
+
+// RetryPolicy configures how a `Retry` source retries, the zero value retries with the defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times the source is loaded, including the first. Defaults to 3.
+	MaxAttempts int
+
+	// InitialBackoff is the wait before the first retry. Defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between retries. Defaults to 10s.
+	MaxBackoff time.Duration
+	// Multiplier is the factor the wait grows with after every retry. Defaults to 2.
+	Multiplier float64
+	// Jitter is the fraction of every wait that is randomized, between 0 (the default) and 1.
+	// For example, with a jitter of 0.2 a wait of 1s becomes a random wait between 0.8s and 1s.
+	Jitter float64
+
+	// Retryable reports whether the source should be retried after the given error.
+	// Defaults to `DefaultRetryable`.
+	Retryable func(error) bool
+}
+
+// DefaultRetryable reports whether an error is worth retrying: errors that will not go away by retrying,
+// such as a missing or malformed config (`ErrNotFound` and `ErrParse`), or a cancelled load, are not.
+func DefaultRetryable(err error) bool {
+	return !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrParse) && !errors.Is(err, context.Canceled)
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = defaultRetryAttempts
+	}
+	if p.InitialBackoff == 0 {
+		p.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if p.MaxBackoff == 0 {
+		p.MaxBackoff = defaultRetryMaxBackoff
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = defaultRetryMultiplier
+	}
+	if p.Retryable == nil {
+		p.Retryable = DefaultRetryable
+	}
+	return p
+}
+
+func (p RetryPolicy) validate() error {
+	switch {
+	case p.MaxAttempts < 0:
+		return fmt.Errorf("max attempts cannot be negative")
+	case p.InitialBackoff < 0 || p.MaxBackoff < 0:
+		return fmt.Errorf("backoff cannot be negative")
+	case p.Multiplier < 0:
+		return fmt.Errorf("backoff multiplier cannot be negative")
+	case p.Jitter < 0 || p.Jitter > 1:
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// backoff returns the wait before the given retry, starting at 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	backoff := float64(p.InitialBackoff)
+	for i := 1; i < retry && backoff < float64(p.MaxBackoff); i++ {
+		backoff *= p.Multiplier
+	}
+	backoff = min(backoff, float64(p.MaxBackoff))
+	backoff -= backoff * p.Jitter * rand.Float64() //nolint:gosec // Jitter does not need to be secure.
+	return time.Duration(backoff)
+}
+
+// Retry wraps a source and retries loading it when it fails, waiting with exponential backoff between
+// attempts. Only errors that the policy considers retryable are retried, see `RetryPolicy`.
+// Retrying stops when the load times out, the error of the last attempt is returned.
+//
+// To limit how long every attempt may take, wrap the source with `Timeout` first:
+//
+//	Retry(Timeout(Remote[*AppConfig](url, opts), 2*time.Second), RetryPolicy{MaxAttempts: 5})
+func Retry[C ConfigModel](src SourceFunc[C], policy RetryPolicy) SourceFunc[C] {
+	return func(mgr *Config[C]) (Source, error) {
+		if src == nil {
+			return Source{}, fmt.Errorf("source cannot be nil")
+		}
+		if err := policy.validate(); err != nil {
+			return Source{}, fmt.Errorf("invalid retry policy: %w", err)
+		}
+		policy := policy.withDefaults()
+
+		innerSrc, err := src(mgr)
+		if err != nil {
+			return Source{}, err
+		}
+
+		src := innerSrc
+		src.Load = func(ctx context.Context, k *koanf.Koanf) error {
+			for attempt := 1; ; attempt++ {
+				// Every attempt loads into a new instance, so that failed attempts leave nothing behind.
+				layer, err := loadLayer(ctx, innerSrc, k.Delim())
+				if err == nil {
+					return k.Merge(layer)
+				}
+				if attempt >= policy.MaxAttempts || !policy.Retryable(err) {
+					return err
+				}
+
+				backoff := policy.backoff(attempt)
+				mgr.log(ctx, slog.LevelDebug, "retrying config source",
+					slog.String("type", innerSrc.Type.String()),
+					slog.String("location", innerSrc.Location),
+					slog.Int("attempt", attempt),
+					slog.Duration("backoff", backoff),
+					slog.String("error", err.Error()))
+
+				timer := time.NewTimer(backoff)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return fmt.Errorf("%w (stopped retrying after %d attempts: %w)", err, attempt, ctx.Err())
+				case <-timer.C:
+				}
+			}
+		}
+		return src, nil
+	}
+}
+
+// Timeout wraps a source and limits how long loading it may take, on top of the timeout of
+// the whole load (see `WithLoadTimeout`). The source must respect the context it is given.
+func Timeout[C ConfigModel](src SourceFunc[C], timeout time.Duration) SourceFunc[C] {
+	return func(mgr *Config[C]) (Source, error) {
+		if src == nil {
+			return Source{}, fmt.Errorf("source cannot be nil")
+		}
+		if timeout <= 0 {
+			return Source{}, fmt.Errorf("timeout must be positive")
+		}
+
+		innerSrc, err := src(mgr)
+		if err != nil {
+			return Source{}, err
+		}
+
+		src := innerSrc
+		src.Load = func(ctx context.Context, k *koanf.Koanf) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			err := innerSrc.Load(ctx, k)
+			if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return fmt.Errorf("source timed out after %s: %w", timeout, err)
+			}
+			return err
+		}
+		return src, nil
+	}
+}
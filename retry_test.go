@@ -0,0 +1,132 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/knadh/koanf/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakySource fails the given number of times, after loading a partial value, and then succeeds.
+func flakySource(failures int, err error, attempts *int) SourceFunc[*TestModel] {
+	return func(mgr *Config[*TestModel]) (Source, error) {
+		return Source{
+			Type:     SourceTypeStruct,
+			Location: "flaky",
+			Load: func(ctx context.Context, k *koanf.Koanf) error {
+				*attempts++
+				if *attempts <= failures {
+					_ = k.Set("abc", "partial")
+					return err
+				}
+				return k.Set("key", "loaded")
+			},
+		}, nil
+	}
+}
+
+func TestRetry(t *testing.T) {
+	errFlaky := errors.New("flaky")
+	policy := RetryPolicy{InitialBackoff: time.Millisecond}
+
+	t.Run("Succeeds after retries", func(t *testing.T) {
+		var attempts int
+		cfg, err := Init(&TestModel{}, WithSource(Retry(flakySource(2, errFlaky, &attempts), policy)))
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, "loaded", cfg.Model().Key)
+		// Values of failed attempts are not kept
+		assert.Empty(t, cfg.Model().ABC)
+	})
+
+	t.Run("Max attempts", func(t *testing.T) {
+		var attempts int
+		_, err := Init(&TestModel{}, WithSource(Retry(flakySource(5, errFlaky, &attempts), policy)))
+		assert.ErrorIs(t, err, errFlaky)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("Not retryable", func(t *testing.T) {
+		var attempts int
+		_, err := Init(&TestModel{}, WithSource(Retry(flakySource(5, &NotFoundError{}, &attempts), policy)))
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, 1, attempts)
+
+		attempts = 0
+		custom := RetryPolicy{InitialBackoff: time.Millisecond, MaxAttempts: 4, Retryable: func(err error) bool { return true }}
+		_, err = Init(&TestModel{}, WithSource(Retry(flakySource(5, &NotFoundError{}, &attempts), custom)))
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.Equal(t, 4, attempts)
+	})
+
+	t.Run("Stops at load timeout", func(t *testing.T) {
+		var attempts int
+		slow := RetryPolicy{InitialBackoff: time.Hour, MaxAttempts: 10}
+		_, err := Init(&TestModel{},
+			WithLoadTimeout[*TestModel](20*time.Millisecond),
+			WithSource(Retry(flakySource(5, errFlaky, &attempts), slow)),
+		)
+		assert.ErrorIs(t, err, errFlaky)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		p := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}.withDefaults()
+		assert.Equal(t, time.Second, p.backoff(1))
+		assert.Equal(t, 2*time.Second, p.backoff(2))
+		assert.Equal(t, 4*time.Second, p.backoff(3))
+		assert.Equal(t, 5*time.Second, p.backoff(4))
+		assert.Equal(t, 5*time.Second, p.backoff(100))
+
+		p.Jitter = 0.5
+		for i := 0; i < 100; i++ {
+			backoff := p.backoff(2)
+			assert.GreaterOrEqual(t, backoff, time.Second)
+			assert.LessOrEqual(t, backoff, 2*time.Second)
+		}
+	})
+
+	t.Run("Invalid policy", func(t *testing.T) {
+		var attempts int
+		for _, p := range []RetryPolicy{
+			{MaxAttempts: -1},
+			{InitialBackoff: -time.Second},
+			{Multiplier: -1},
+			{Jitter: 2},
+		} {
+			_, err := New(&TestModel{}, WithSource(Retry(flakySource(0, nil, &attempts), p)))
+			assert.Error(t, err)
+		}
+		_, err := New(&TestModel{}, WithSource(Retry[*TestModel](nil, policy)))
+		assert.Error(t, err)
+	})
+}
+
+func TestTimeout(t *testing.T) {
+	blocking := func(mgr *Config[*TestModel]) (Source, error) {
+		return Source{
+			Type: SourceTypeStruct,
+			Load: func(ctx context.Context, k *koanf.Koanf) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+		}, nil
+	}
+
+	start := time.Now()
+	_, err := Init(&TestModel{}, WithSource(Timeout(blocking, 10*time.Millisecond)))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "source timed out after 10ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	_, err = New(&TestModel{}, WithSource(Timeout(blocking, 0)))
+	assert.Error(t, err)
+
+	_, err = New(&TestModel{}, WithLoadTimeout[*TestModel](0))
+	assert.Error(t, err)
+}
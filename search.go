@@ -0,0 +1,144 @@
+package ckoanf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// SearchFile is a source that looks for a config file with the given name in the given directories,
+// in order, and loads the first one it finds. For example:
+//
+//	SearchFile[*AppConfig]("config", ".", "$XDG_CONFIG_HOME/app", "~/.config/app", "/etc/app")
+//
+// If the name has no supported extension, every supported extension is tried in each directory (in the
+// order toml, yaml, yml, json), and the filetype is inferred from the extension that is found.
+// Environment variables and a leading `~` in the directories are expanded, `$XDG_CONFIG_HOME` defaults to
+// `~/.config`. Directories that use other unset environment variables are skipped.
+//
+// If no file is found, the error matches `ErrNotFound` and lists every location that was searched.
+func SearchFile[C ConfigModel](name string, paths ...string) SourceFunc[C] {
+	return searchFile[C](name, paths, false)
+}
+
+// SearchFiles is like `SearchFile`, but loads every file it finds instead of only the first one. The files are
+// layered in reverse order: files in the directories that are listed first override the later ones,
+// so `./config.toml` overrides `/etc/app/config.toml`. Only the first extension found is used per directory.
+func SearchFiles[C ConfigModel](name string, paths ...string) SourceFunc[C] {
+	return searchFile[C](name, paths, true)
+}
+
+func searchFile[C ConfigModel](name string, paths []string, layered bool) SourceFunc[C] {
+	return func(mgr *Config[C]) (Source, error) {
+		if name == "" {
+			return Source{}, fmt.Errorf("file name cannot be empty")
+		}
+		if len(paths) == 0 {
+			return Source{}, fmt.Errorf("no search paths given")
+		}
+
+		src := Source{
+			Type:     SourceTypeLocalFile,
+			Location: fmt.Sprintf("%s in %s", name, strings.Join(paths, ", ")),
+			Load: func(ctx context.Context, k *koanf.Koanf) error {
+				var dirs []string
+				for _, path := range paths {
+					if dir, ok := expandPath(path); ok {
+						dirs = append(dirs, dir)
+					}
+				}
+				return loadSearched(ctx, k, name, dirs, layered)
+			},
+		}
+		return src, nil
+	}
+}
+
+// loadSearched loads the first config file with the given name in the directories, or in layered mode all
+// of them, in reverse order.
+func loadSearched(ctx context.Context, k *koanf.Koanf, name string, dirs []string, layered bool) error {
+	found, searched, err := findFiles(name, dirs, !layered)
+	if err == nil && len(found) == 0 {
+		err = &NotFoundError{Locations: searched}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load config from local file: %w", err)
+	}
+
+	slices.Reverse(found)
+	for _, path := range found {
+		if err := loadLocalFile(ctx, k, path); err != nil {
+			return fmt.Errorf("failed to load config from local file: %w", err)
+		}
+	}
+	return nil
+}
+
+// findFiles returns the config files with the given name in the directories in order, and all the
+// locations that were searched. If first is true, it stops at the first file it finds.
+func findFiles(name string, dirs []string, first bool) (found []string, searched []string, err error) {
+	names := []string{name}
+	if !isConfigFile(name) {
+		names = make([]string, len(configFileExtensions))
+		for i, ext := range configFileExtensions {
+			names[i] = name + ext
+		}
+	}
+
+	for _, dir := range dirs {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			searched = append(searched, path)
+
+			info, err := os.Stat(path)
+			if errors.Is(err, fs.ErrNotExist) || (err == nil && info.IsDir()) {
+				continue
+			}
+			if err != nil {
+				return nil, searched, err
+			}
+
+			found = append(found, path)
+			if first {
+				return found, searched, nil
+			}
+			break
+		}
+	}
+	return found, searched, nil
+}
+
+// expandPath expands environment variables and a leading `~` in the path. It returns false if the path
+// uses an environment variable that is not set, or the home directory is not known.
+func expandPath(path string) (string, bool) {
+	ok := true
+	home := func() string {
+		dir, err := os.UserHomeDir()
+		if err != nil {
+			ok = false
+		}
+		return dir
+	}
+
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		path = home() + path[1:]
+	}
+	path = os.Expand(path, func(name string) string {
+		if value, set := os.LookupEnv(name); set && value != "" {
+			return value
+		}
+		if name == "XDG_CONFIG_HOME" {
+			return filepath.Join(home(), ".config")
+		}
+		ok = false
+		return ""
+	})
+	return path, ok
+}
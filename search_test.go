@@ -0,0 +1,89 @@
+package ckoanf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchFile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	local := t.TempDir()
+	etc := t.TempDir()
+	xdg := filepath.Join(home, ".config", "app")
+	require.NoError(t, os.MkdirAll(xdg, 0o700))
+
+	require.NoError(t, os.WriteFile(filepath.Join(etc, "config.toml"), []byte("key = 'etc'\nabc = 'etc'"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(xdg, "config.yaml"), []byte("key: xdg"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(xdg, "config.json"), []byte(`{"key": "json"}`), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(local, "config.toml"), 0o700))
+
+	paths := []string{local, "$XDG_CONFIG_HOME/app", "$SEARCH_TEST_UNSET/app", etc}
+
+	t.Run("First match", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(SearchFile[*TestModel]("config", paths...)))
+		require.NoError(t, err)
+		assert.Equal(t, "xdg", cfg.Model().Key)
+		assert.Empty(t, cfg.Model().ABC)
+
+		exp, _ := cfg.Explain("key")
+		assert.Equal(t, filepath.Join(xdg, "config.yaml"), exp.Origins[0].Location)
+	})
+
+	t.Run("Explicit extension and home", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(SearchFile[*TestModel]("config.json", local, "~/.config/app")))
+		require.NoError(t, err)
+		assert.Equal(t, "json", cfg.Model().Key)
+	})
+
+	t.Run("Layered", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(SearchFiles[*TestModel]("config", paths...)))
+		require.NoError(t, err)
+		assert.Equal(t, "xdg", cfg.Model().Key)
+		assert.Equal(t, "etc", cfg.Model().ABC)
+
+		exp, _ := cfg.Explain("key")
+		require.Len(t, exp.Origins, 2)
+		assert.Equal(t, filepath.Join(etc, "config.toml"), exp.Origins[0].Location)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := Init(&TestModel{}, WithSource(SearchFile[*TestModel]("missing", local, "$XDG_CONFIG_HOME/app")))
+		require.ErrorIs(t, err, ErrNotFound)
+
+		var notFound *NotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, []string{
+			filepath.Join(local, "missing.toml"),
+			filepath.Join(local, "missing.yaml"),
+			filepath.Join(local, "missing.yml"),
+			filepath.Join(local, "missing.json"),
+			filepath.Join(xdg, "missing.toml"),
+			filepath.Join(xdg, "missing.yaml"),
+			filepath.Join(xdg, "missing.yml"),
+			filepath.Join(xdg, "missing.json"),
+		}, notFound.Locations)
+		assert.Contains(t, err.Error(), "config not found at "+filepath.Join(local, "missing.toml")+", ")
+
+		cfg, err := Init(&TestModel{}, WithSource(
+			EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML),
+			OptionalSource(SearchFiles[*TestModel]("missing", paths...), ErrNotFound),
+		))
+		require.NoError(t, err)
+		assert.Equal(t, "value", cfg.Model().Key)
+		assert.Equal(t, SourceStatusSkipped, cfg.Report().Sources[1].Status)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := New(&TestModel{}, WithSource(SearchFile[*TestModel]("", local)))
+		assert.Error(t, err)
+		_, err = New(&TestModel{}, WithSource(SearchFile[*TestModel]("config")))
+		assert.Error(t, err)
+	})
+}
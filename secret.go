@@ -0,0 +1,272 @@
+package ckoanf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/knadh/koanf/v2"
+)
+
+// Secret holds a config value that must not be leaked, such as a password or an API token.
+// It is unmarshalled like a plain T, but redacts itself when formatted, marshalled to JSON or logged.
+// Keys of Secret fields are also redacted by the config manager itself, for example in its logs.
+//
+// Use `Value` to get the actual value.
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret returns a secret holding the given value.
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value: value}
+}
+
+// Value returns the actual value of the secret.
+//
+//nolint:ireturn,nolintlint // Generic return, false positive by the linter
+func (s Secret[T]) Value() T {
+	return s.value
+}
+
+// String implements fmt.Stringer, it always returns `Redacted`.
+func (s Secret[T]) String() string {
+	return Redacted
+}
+
+// GoString implements fmt.GoStringer, it always returns `Redacted`.
+func (s Secret[T]) GoString() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter, so that every verb prints `Redacted`.
+func (s Secret[T]) Format(f fmt.State, _ rune) {
+	_, _ = io.WriteString(f, Redacted)
+}
+
+// MarshalJSON implements json.Marshaler, the secret is marshalled as `Redacted`.
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// LogValue implements slog.LogValuer, the secret is logged as `Redacted`.
+func (s Secret[T]) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+func (s *Secret[T]) decodeSecret(decode func(input, output interface{}) error, input interface{}) error {
+	return decode(input, &s.value)
+}
+
+// secretDecoder is implemented by pointers to secrets, so that they can be unmarshalled into.
+type secretDecoder interface {
+	decodeSecret(decode func(input, output interface{}) error, input interface{}) error
+}
+
+//nolint:gochecknoglobals // Constant type.
+var secretDecoderType = reflect.TypeOf((*secretDecoder)(nil)).Elem()
+
+// unmarshal unmarshals the koanf instance into the model like koanf does by default,
+// and additionally decodes `Secret` fields from their plain values.
+func unmarshal(k *koanf.Koanf, out interface{}) error {
+	return k.UnmarshalWithConf("", out, koanf.UnmarshalConf{DecoderConfig: decoderConfig(out)})
+}
+
+func decoderConfig(out interface{}) *mapstructure.DecoderConfig {
+	return &mapstructure.DecoderConfig{
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			secretHookFunc(),
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.TextUnmarshallerHookFunc()),
+		Result:           out,
+		TagName:          "koanf",
+		WeaklyTypedInput: true,
+	}
+}
+
+func secretHookFunc() mapstructure.DecodeHookFuncType {
+	decode := func(input, output interface{}) error {
+		d, err := mapstructure.NewDecoder(decoderConfig(output))
+		if err != nil {
+			return err
+		}
+		return d.Decode(input)
+	}
+
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if from == to || !reflect.PointerTo(to).Implements(secretDecoderType) {
+			return data, nil
+		}
+
+		secret := reflect.New(to)
+		//nolint:forcetypeassert // Checked above.
+		if err := secret.Interface().(secretDecoder).decodeSecret(decode, data); err != nil {
+			return nil, err
+		}
+		return secret.Elem().Interface(), nil
+	}
+}
+
+// modelSecretKeys returns the key paths of all `Secret` fields and fields tagged `secret:"true"` in the
+// given model type. Values of maps are matched with a `*` segment. Slices that contain secrets are
+// secret as a whole, as koanf does not split them into separate keys.
+//
+// Visiting holds the types that are being walked, types that contain themselves are only walked once.
+func modelSecretKeys(t reflect.Type, prefix string, visiting map[reflect.Type]bool) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visiting[t] {
+		return nil
+	}
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(field.Tag.Get("koanf"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		path := prefix + name
+		if strings.Contains(opts, "squash") {
+			path = strings.TrimSuffix(prefix, defaultDelimiter)
+		}
+
+		if field.Tag.Get("secret") == "true" {
+			keys = append(keys, path)
+			continue
+		}
+		keys = append(keys, typeSecretKeys(field.Type, path, visiting)...)
+	}
+	return keys
+}
+
+// typeSecretKeys returns the secret key paths of a value of the given type at the given path.
+func typeSecretKeys(t reflect.Type, path string, visiting map[reflect.Type]bool) []string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(secretDecoderType) {
+		return []string{path}
+	}
+
+	switch t.Kind() { //nolint:exhaustive // Other kinds can not hold secrets.
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		if visiting[t] {
+			// The type contains itself, so its keys can be nested endlessly. If it holds secrets,
+			// the whole value is secret.
+			if typeHasSecrets(t, make(map[reflect.Type]bool)) {
+				return []string{path}
+			}
+			return nil
+		}
+	}
+
+	switch t.Kind() { //nolint:exhaustive // Other kinds can not hold secrets.
+	case reflect.Struct:
+		return modelSecretKeys(t, joinPrefix(path), visiting)
+	case reflect.Map, reflect.Slice, reflect.Array:
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		if t.Kind() == reflect.Map {
+			return typeSecretKeys(t.Elem(), joinPrefix(path)+"*", visiting)
+		}
+		if len(typeSecretKeys(t.Elem(), "", visiting)) > 0 {
+			return []string{path}
+		}
+	}
+	return nil
+}
+
+// typeHasSecrets reports whether values of the given type can hold secrets. Seen holds the types that
+// were already checked.
+func typeHasSecrets(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(secretDecoderType) {
+		return true
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() { //nolint:exhaustive // Other kinds can not hold secrets.
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.IsExported() && (field.Tag.Get("secret") == "true" || typeHasSecrets(field.Type, seen)) {
+				return true
+			}
+		}
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return typeHasSecrets(t.Elem(), seen)
+	}
+	return false
+}
+
+func joinPrefix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return path + defaultDelimiter
+}
+
+// matchesSecretKey reports whether the key is equal to or below the secret key path,
+// where a `*` segment in the secret key path matches any single segment.
+func matchesSecretKey(key, secret string) bool {
+	keyParts := strings.Split(key, defaultDelimiter)
+	secretParts := strings.Split(secret, defaultDelimiter)
+	if len(keyParts) < len(secretParts) {
+		return false
+	}
+	for i, part := range secretParts {
+		if part != "*" && part != keyParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// redactTree returns the value at the given key path with all secrets in it redacted,
+// nested maps are copied rather than modified.
+func (mgr *Config[C]) redactTree(key string, value interface{}) interface{} {
+	if mgr.IsSecret(key) {
+		return Redacted
+	}
+
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+
+	redacted := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		redacted[k] = mgr.redactTree(joinPrefix(key)+k, v)
+	}
+	return redacted
+}
+
+// All returns the flattened key paths and values of the current config, like koanf's `All`,
+// but with the values of secrets redacted.
+func (mgr *Config[C]) All() map[string]interface{} {
+	all := mgr.current.Load().k.All()
+	for key := range all {
+		if mgr.IsSecret(key) {
+			all[key] = Redacted
+		}
+	}
+	return all
+}
@@ -0,0 +1,189 @@
+package ckoanf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SecretModel struct {
+	Name     string                    `koanf:"name"`
+	Password Secret[string]            `koanf:"password"`
+	Pin      Secret[int]               `koanf:"pin"`
+	Token    string                    `koanf:"token" secret:"true"`
+	DB       SecretDB                  `koanf:"db"`
+	Backends map[string]SecretDB       `koanf:"backends"`
+	Replicas []SecretDB                `koanf:"replicas"`
+	Optional *Secret[string]           `koanf:"optional"`
+	Labels   map[string]string         `koanf:"labels"`
+	Extra    map[string]Secret[string] `koanf:"extra"`
+}
+
+type SecretDB struct {
+	Host     string         `koanf:"host"`
+	Password Secret[string] `koanf:"password"`
+}
+
+func (m *SecretModel) Validate() error {
+	return nil
+}
+
+type RecursiveModel struct {
+	Root  RecursiveNode            `koanf:"root"`
+	Index map[string]RecursiveNode `koanf:"index"`
+}
+
+type RecursiveNode struct {
+	Name     string          `koanf:"name"`
+	Token    Secret[string]  `koanf:"token"`
+	Children []RecursiveNode `koanf:"children"`
+	Next     *RecursiveNode  `koanf:"next"`
+}
+
+func (m *RecursiveModel) Validate() error {
+	return nil
+}
+
+const secretFixture = `
+name = "app"
+password = "hunter2"
+pin = "1234"
+token = "tok"
+optional = "opt"
+
+[db]
+host = "localhost"
+password = "dbpass"
+
+[backends.eu]
+host = "eu.local"
+password = "eupass"
+
+[labels]
+env = "prod"
+
+[extra]
+key = "extrapass"
+
+[[replicas]]
+host = "replica"
+password = "replicapass"
+`
+
+func TestSecret(t *testing.T) {
+	cfg, err := Init(&SecretModel{}, WithSource(EmbeddedDefaults[*SecretModel]([]byte(secretFixture), FileTypeTOML)))
+	require.NoError(t, err)
+
+	model := cfg.Model()
+	assert.Equal(t, "hunter2", model.Password.Value())
+	assert.Equal(t, 1234, model.Pin.Value())
+	assert.Equal(t, "tok", model.Token)
+	assert.Equal(t, "dbpass", model.DB.Password.Value())
+	assert.Equal(t, "eupass", model.Backends["eu"].Password.Value())
+	assert.Equal(t, "replicapass", model.Replicas[0].Password.Value())
+	assert.Equal(t, "opt", model.Optional.Value())
+	assert.Equal(t, "extrapass", model.Extra["key"].Value())
+
+	t.Run("Formatting", func(t *testing.T) {
+		for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%d"} {
+			out := fmt.Sprintf(format, model.DB)
+			assert.NotContains(t, out, "dbpass", format)
+			assert.Contains(t, out, Redacted, format)
+		}
+		assert.Equal(t, Redacted, model.Password.String())
+		assert.Equal(t, Redacted, fmt.Sprint(model.Pin))
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		b, err := json.Marshal(model.DB)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"Host": "localhost", "Password": "[REDACTED]"}`, string(b))
+	})
+
+	t.Run("slog", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, nil))
+		logger.Info("test", "password", model.Password, "db", model.DB)
+		assert.NotContains(t, buf.String(), "hunter2")
+		assert.NotContains(t, buf.String(), "dbpass")
+	})
+
+	t.Run("IsSecret", func(t *testing.T) {
+		for _, key := range []string{
+			"password", "pin", "token", "optional", "db.password", "backends.eu.password", "replicas", "extra.key",
+		} {
+			assert.True(t, cfg.IsSecret(key), key)
+		}
+		for _, key := range []string{"name", "db.host", "backends.eu.host", "labels.env", "db"} {
+			assert.False(t, cfg.IsSecret(key), key)
+		}
+	})
+
+	t.Run("Explain and All", func(t *testing.T) {
+		exp, ok := cfg.Explain("db")
+		require.True(t, ok)
+		assert.Equal(t, map[string]interface{}{"host": "localhost", "password": Redacted}, exp.Value)
+		for _, origin := range exp.Origins {
+			if origin.Key == "db.password" {
+				assert.Equal(t, Redacted, origin.Value)
+			}
+		}
+
+		all := cfg.All()
+		assert.Equal(t, Redacted, all["password"])
+		assert.Equal(t, Redacted, all["backends.eu.password"])
+		assert.Equal(t, "eu.local", all["backends.eu.host"])
+		assert.Equal(t, "hunter2", cfg.K.String("password"))
+	})
+
+	t.Run("Set subtree", func(t *testing.T) {
+		var buf bytes.Buffer
+		cfg, err := Init(&SecretModel{},
+			WithLogger[*SecretModel](slog.New(slog.NewTextHandler(&buf, nil))),
+			WithSource(EmbeddedDefaults[*SecretModel]([]byte(secretFixture), FileTypeTOML)),
+		)
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.Set("db", map[string]interface{}{"host": "x", "password": "supersecret"}))
+		assert.Equal(t, "supersecret", cfg.Model().DB.Password.Value())
+		assert.Contains(t, buf.String(), "set config value")
+		assert.NotContains(t, buf.String(), "supersecret")
+	})
+
+	t.Run("Invalid value", func(t *testing.T) {
+		_, err := Init(&SecretModel{}, WithSource(EmbeddedDefaults[*SecretModel]([]byte("pin = 'abc'"), FileTypeTOML)))
+		var unmarshalErr *UnmarshalError
+		assert.ErrorAs(t, err, &unmarshalErr)
+	})
+}
+
+func TestSecretRecursiveModel(t *testing.T) {
+	cfg, err := Init(&RecursiveModel{}, WithSource(EmbeddedDefaults[*RecursiveModel]([]byte(`
+[root]
+name = "root"
+token = "roottoken"
+
+[root.next]
+name = "next"
+token = "nexttoken"
+
+[[root.children]]
+name = "child"
+token = "childtoken"
+`), FileTypeTOML)))
+	require.NoError(t, err)
+	assert.Equal(t, "nexttoken", cfg.Model().Root.Next.Token.Value())
+	assert.Equal(t, "childtoken", cfg.Model().Root.Children[0].Token.Value())
+
+	for _, key := range []string{"root.token", "root.children", "root.next", "root.next.name", "index.a.token"} {
+		assert.True(t, cfg.IsSecret(key), key)
+	}
+	for _, key := range []string{"root.name", "index.a.name"} {
+		assert.False(t, cfg.IsSecret(key), key)
+	}
+}
@@ -0,0 +1,115 @@
+package ckoanf
+
+import (
+	"reflect"
+
+	"github.com/knadh/koanf/v2"
+)
+
+// snapshot is an immutable view of the config after a successful load or set.
+type snapshot[C ConfigModel] struct {
+	k       *koanf.Koanf
+	model   C
+	origins []Origin
+}
+
+// Snapshot returns the model of the last successful load or set.
+//
+// Unlike `Model`, it is safe to call concurrently with `Load`, `Set` and reloads, and it never blocks.
+// A snapshot is never updated after it is returned, a later load or set creates a new one instead.
+// The returned model is shared between all callers and must not be modified.
+//
+//nolint:ireturn,nolintlint // Generic return, false positive by the linter
+func (mgr *Config[C]) Snapshot() C {
+	return mgr.current.Load().model
+}
+
+// View calls the given function with the current snapshot of the model, see `Snapshot`.
+func (mgr *Config[C]) View(fn func(C)) {
+	fn(mgr.Snapshot())
+}
+
+// cloneModel returns a deep copy of the value a model pointer points to, so that unmarshalling into
+// the copy does not touch maps, slices or pointers that are shared with the original.
+// Non-pointer models are returned as is, they can not be unmarshalled into anyway.
+//
+//nolint:ireturn,nolintlint // Generic return, false positive by the linter
+func cloneModel[C ConfigModel](c C) C {
+	v := reflect.ValueOf(c)
+	if !v.IsValid() || v.Kind() != reflect.Pointer || v.IsNil() {
+		return c
+	}
+
+	return deepCopy(v).Interface().(C) //nolint:forcetypeassert // Same type as c.
+}
+
+// assignModel copies the value src points to into dst, so that existing references to the
+// model (such as the one passed to `New`) observe the new values. It returns the model to keep.
+//
+//nolint:ireturn,nolintlint // Generic return, false positive by the linter
+func assignModel[C ConfigModel](dst, src C) C {
+	dv := reflect.ValueOf(dst)
+	if !dv.IsValid() || dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return src
+	}
+
+	dv.Elem().Set(reflect.ValueOf(src).Elem())
+	return dst
+}
+
+// deepCopy copies pointers, structs, maps, slices, arrays and interfaces recursively.
+// Unexported struct fields can not be set through reflection, so they are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Elem().Type())
+		cp.Elem().Set(deepCopy(v.Elem()))
+		return cp
+	case reflect.Struct:
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if cp.Field(i).CanSet() {
+				cp.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return cp
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return cp
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+	case reflect.Array:
+		cp := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(deepCopy(v.Elem()))
+		return cp
+	default:
+		return v
+	}
+}
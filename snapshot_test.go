@@ -0,0 +1,100 @@
+package ckoanf
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SnapshotModel struct {
+	Key    string            `koanf:"key"`
+	Labels map[string]string `koanf:"labels"`
+	Ports  []int             `koanf:"ports"`
+	Nested *Nested           `koanf:"nested"`
+}
+
+func (m *SnapshotModel) Validate() error {
+	return nil
+}
+
+func TestSnapshot(t *testing.T) {
+	t.Run("Snapshots are not modified by later sets", func(t *testing.T) {
+		model := &SnapshotModel{
+			Labels: map[string]string{"initial": "yes"},
+			Nested: &Nested{Foo: "initial"},
+		}
+		cfg, err := Init(model, WithSource(EmbeddedDefaults[*SnapshotModel](
+			[]byte("key = 'a'\nports = [1, 2]\n[labels]\nenv = 'dev'\n[nested]\nfoo = 'bar'"), FileTypeTOML)),
+		)
+		require.NoError(t, err)
+
+		first := cfg.Snapshot()
+		assert.Equal(t, "a", first.Key)
+		assert.Equal(t, map[string]string{"initial": "yes", "env": "dev"}, first.Labels)
+		assert.NotSame(t, model, first)
+
+		require.NoError(t, cfg.Set("key", "b"))
+		require.NoError(t, cfg.Set("labels.env", "prod"))
+		require.NoError(t, cfg.Set("ports", []int{3}))
+		require.NoError(t, cfg.Set("nested.foo", "baz"))
+
+		assert.Equal(t, "a", first.Key)
+		assert.Equal(t, "dev", first.Labels["env"])
+		assert.Equal(t, []int{1, 2}, first.Ports)
+		assert.Equal(t, "bar", first.Nested.Foo)
+
+		cfg.View(func(m *SnapshotModel) {
+			assert.Equal(t, "b", m.Key)
+			assert.Equal(t, "prod", m.Labels["env"])
+			assert.Equal(t, []int{3}, m.Ports)
+			assert.Equal(t, "baz", m.Nested.Foo)
+		})
+
+		// The model passed to New is updated in place, but does not share data with snapshots
+		assert.Equal(t, "b", model.Key)
+		model.Labels["env"] = "modified"
+		assert.Equal(t, "prod", cfg.Snapshot().Labels["env"])
+	})
+
+	t.Run("Snapshot before Load", func(t *testing.T) {
+		cfg, err := New(&TestModel{Key: "initial"})
+		require.NoError(t, err)
+		assert.Equal(t, "initial", cfg.Snapshot().Key)
+	})
+
+	t.Run("Concurrent readers and writers", func(t *testing.T) {
+		cfg, err := Init(&TestModel{}, WithSource(EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML)))
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 50; i++ {
+					if i%5 == 0 {
+						assert.NoError(t, cfg.Load())
+					} else {
+						assert.NoError(t, cfg.Set("nested.foo", fmt.Sprintf("%d-%d", w, i)))
+					}
+				}
+			}(w)
+		}
+		for r := 0; r < 4; r++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 200; i++ {
+					cfg.View(func(m *TestModel) {
+						assert.Equal(t, "value", m.Key)
+						assert.NotEmpty(t, m.Nested.Foo)
+					})
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -8,9 +8,27 @@ import (
 )
 
 // Source is a config source, which is something that can be loaded into a koanf config.
+//
+// Load is given an empty koanf instance that only holds the values of this source, the config manager
+// merges it into the config after Load returns. Sources that need the values of the sources before
+// them can get those with `Merged`.
 type Source struct {
 	Type SourceType
 	Load func(context.Context, *koanf.Koanf) error
+
+	// Location is optional and describes where the source reads from, such as a file path.
+	// It is used in provenance, see `Config.Explain`.
+	Location string
+
+	// Watch is optional, if set it starts watching the source for changes in the background
+	// and calls the given function whenever it changes. Watching stops when the context is done.
+	Watch func(ctx context.Context, notify func()) error
+
+	// Parallel marks that the source can be loaded concurrently with the other parallel sources, see `Parallel`.
+	Parallel bool
+
+	// Whether the source is created at load time, see `Deferred`.
+	deferred bool
 }
 
 type SourceType string
@@ -21,6 +39,13 @@ const (
 	SourceTypeEnv       SourceType = "env"
 	SourceTypePFlag     SourceType = "pflag"
 	SourceTypeStruct    SourceType = "struct"
+	SourceTypeRemote    SourceType = "remote"
+	SourceTypeDirectory SourceType = "directory"
+	SourceTypeDeferred  SourceType = "deferred"
+
+	// SourceTypeSet is used in provenance for values changed with `Config.Set`.
+	// It is not a valid type for a source.
+	SourceTypeSet SourceType = "set"
 )
 
 func (p SourceType) String() string {
@@ -29,9 +54,106 @@ func (p SourceType) String() string {
 
 func (p SourceType) Valid() error {
 	switch p {
-	case SourceTypeDefault, SourceTypeLocalFile, SourceTypeEnv, SourceTypePFlag, SourceTypeStruct:
+	case SourceTypeDefault, SourceTypeLocalFile, SourceTypeEnv, SourceTypePFlag, SourceTypeStruct, SourceTypeRemote,
+		SourceTypeDirectory, SourceTypeDeferred:
 		return nil
 	default:
 		return fmt.Errorf("invalid provider type: %s", p)
 	}
 }
+
+// sourceState is passed to a source's Load function through the context.
+type sourceState struct {
+	// The config merged from the sources before the current one.
+	merged *koanf.Koanf
+	// Whether the source read the merged config.
+	readMerged bool
+
+	// Origins recorded by the source, if empty the whole source is a single origin.
+	origins []layerOrigin
+
+	// Why the source was skipped, if it was.
+	skipped error
+	// Why the source fell back to its cache, if it did.
+	fallback error
+	// The source a deferred source resolved to, if it did.
+	resolved *Source
+
+	// Whether profiles are enabled and the active profile, see `WithProfile`.
+	profilesEnabled bool
+	profile         string
+	// The profiles the source defined, in sections or profile files.
+	profiles []string
+
+	// Whether override blocks are extracted from config documents, see `WithAttributes`.
+	overrides bool
+	// The override blocks the source defined.
+	blocks []overrideBlock
+}
+
+// layerOrigin is a set of flattened values that came from a single location.
+type layerOrigin struct {
+	location string
+	values   map[string]interface{}
+	// The line every key was defined on, if known.
+	lines map[string]int
+	// The profile the values only apply to, if any.
+	profile string
+}
+
+type sourceStateKey struct{}
+
+func withSourceState(ctx context.Context, state *sourceState) context.Context {
+	return context.WithValue(ctx, sourceStateKey{}, state)
+}
+
+func sourceStateFrom(ctx context.Context) *sourceState {
+	state, _ := ctx.Value(sourceStateKey{}).(*sourceState)
+	return state
+}
+
+// Merged returns the config merged from the sources before the one currently being loaded.
+// It can be used in the Load function of a source, outside of one, or in a parallel source, it returns nil.
+// The returned koanf instance must not be modified.
+func Merged(ctx context.Context) *koanf.Koanf {
+	if state := sourceStateFrom(ctx); state != nil {
+		state.readMerged = state.readMerged || state.merged != nil
+		return state.merged
+	}
+	return nil
+}
+
+// recordOrigin records that the given flattened values were loaded from the given location.
+// Sources that combine values from several locations use it for more precise provenance.
+// The lines the keys were defined on are optional.
+func recordOrigin(ctx context.Context, location string, values map[string]interface{}, lines map[string]int) {
+	recordProfileOrigin(ctx, location, values, lines, "")
+}
+
+// recordProfileOrigin is like recordOrigin, for values that only apply to the given profile.
+func recordProfileOrigin(
+	ctx context.Context, location string, values map[string]interface{}, lines map[string]int, profile string,
+) {
+	if state := sourceStateFrom(ctx); state != nil {
+		state.origins = append(state.origins, layerOrigin{location: location, values: values, lines: lines, profile: profile})
+	}
+}
+
+// loadLayer loads the source into a new koanf instance with the given delimiter, so that a failed
+// load leaves nothing behind. If it fails, the origins and override blocks the source recorded are discarded as well.
+func loadLayer(ctx context.Context, src Source, delim string) (*koanf.Koanf, error) {
+	state := sourceStateFrom(ctx)
+	var recorded, blocks int
+	if state != nil {
+		recorded, blocks = len(state.origins), len(state.blocks)
+	}
+
+	layer := koanf.New(delim)
+	if err := src.Load(ctx, layer); err != nil {
+		if state != nil {
+			state.origins, state.blocks = state.origins[:recorded], state.blocks[:blocks]
+		}
+		return nil, err
+	}
+	return layer, nil
+}
@@ -7,9 +7,7 @@ import (
 	"strings"
 
 	"github.com/knadh/koanf/providers/env"
-	"github.com/knadh/koanf/providers/file"
 	"github.com/knadh/koanf/providers/posflag"
-	"github.com/knadh/koanf/providers/rawbytes"
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/knadh/koanf/v2"
 	"github.com/spf13/pflag"
@@ -22,6 +20,12 @@ type SourceFunc[C ConfigModel] func(*Config[C]) (Source, error)
 //
 // If the source fails to load, but the error is allowed, the source will be skipped.
 // If no allowed errors are provided, all errors are allowed.
+// Skipped sources and the reason they were skipped are listed in the load report, see `Config.Report`.
+//
+// Errors are matched with `errors.Is`, so kinds of errors can be allowed with the sentinel errors
+// such as `ErrNotFound`. For example, to allow the file to be missing but not to be malformed:
+//
+//	OptionalSource(LocalFile[*AppConfig]("config.toml"), ErrNotFound)
 func OptionalSource[C ConfigModel](src SourceFunc[C], allowedErrors ...error) SourceFunc[C] {
 	isAllowedError := func(err error) bool {
 		if len(allowedErrors) == 0 {
@@ -44,34 +48,56 @@ func OptionalSource[C ConfigModel](src SourceFunc[C], allowedErrors ...error) So
 		if err != nil {
 			return Source{}, err
 		}
-		src := Source{
-			Type: innerSrc.Type,
-			Load: func(ctx context.Context, k *koanf.Koanf) error {
-				err := innerSrc.Load(ctx, k)
-				if err != nil && !isAllowedError(err) {
+		src := innerSrc
+		src.Load = func(ctx context.Context, k *koanf.Koanf) error {
+			err := innerSrc.Load(ctx, k)
+			if err != nil {
+				if !isAllowedError(err) {
 					return err
 				}
-				return nil
-			},
+				markSkipped(ctx, err)
+			}
+			return nil
 		}
 
 		return src, nil
 	}
 }
 
+// Parallel wraps a source so that it is loaded concurrently with the other parallel sources, which
+// speeds up loading sources that are slow to fetch, such as `Remote`.
+//
+// All parallel sources start loading at the start of a load, but are still merged in the order the sources
+// were added, so which source overrides which does not change. As a consequence, parallel sources can not
+// depend on the sources before them: `Merged` returns nil for them.
+func Parallel[C ConfigModel](src SourceFunc[C]) SourceFunc[C] {
+	return func(mgr *Config[C]) (Source, error) {
+		if src == nil {
+			return Source{}, fmt.Errorf("source cannot be nil")
+		}
+
+		innerSrc, err := src(mgr)
+		if err != nil {
+			return Source{}, err
+		}
+		innerSrc.Parallel = true
+		return innerSrc, nil
+	}
+}
+
 // EmbeddedDefaults is a source that loads the config from an embedded config file.
 func EmbeddedDefaults[C ConfigModel](b []byte, filetype ConfigFileType) SourceFunc[C] {
 	return func(mgr *Config[C]) (Source, error) {
 		if err := filetype.Valid(); err != nil {
 			return Source{}, err
 		}
-		parser := filetype.Parser()
-		kprovider := rawbytes.Provider(b)
+		location := "embedded " + filetype.String()
 
 		src := Source{
-			Type: SourceTypeDefault,
+			Type:     SourceTypeDefault,
+			Location: location,
 			Load: func(ctx context.Context, k *koanf.Koanf) error {
-				err := k.Load(kprovider, parser)
+				err := loadDocument(ctx, k, b, filetype, location)
 				if err != nil {
 					return fmt.Errorf("failed to load config from embedded defaults: %w", err)
 				}
@@ -84,47 +110,81 @@ func EmbeddedDefaults[C ConfigModel](b []byte, filetype ConfigFileType) SourceFu
 
 // LocalFile is a source that loads the config from a local file.
 // The filetype is inferred from the file extension.
+//
+// The file can include other files with a top-level `include` key, a path or a list of paths that are relative
+// to the including file and may be glob patterns (see `filepath.Glob`):
+//
+//	include = ["base.toml", "secrets/*.toml"]
+//
+// Included files are merged in order before the values of the including file, so the including file
+// overrides them. They can include files themselves, up to a depth of 8, but not the files that include them.
+// Every included file is its own location in provenance, see `Config.Explain`. Included files are not watched.
+// Only a missing file matches `ErrNotFound`, a missing included file does not, so it also fails optional sources.
+//
+// The file can be watched for changes, see `Config.Watch`.
 func LocalFile[C ConfigModel](filepath string) SourceFunc[C] {
-	filetype := inferConfigFiletype(filepath)
-
 	return func(mgr *Config[C]) (Source, error) {
-		parser := filetype.Parser()
-		kprovider := file.Provider(filepath)
-
 		src := Source{
-			Type: SourceTypeLocalFile,
+			Type:     SourceTypeLocalFile,
+			Location: filepath,
 			Load: func(ctx context.Context, k *koanf.Koanf) error {
-				err := k.Load(kprovider, parser)
-				if err != nil {
+				if err := loadLocalFile(ctx, k, filepath); err != nil {
 					return fmt.Errorf("failed to load config from local file: %w", err)
 				}
 				return nil
 			},
+			Watch: func(ctx context.Context, notify func()) error {
+				if mgr.profile != "" {
+					if err := watchFile(ctx, profilePath(filepath, mgr.profile), notify); err != nil {
+						return err
+					}
+				}
+				return watchFile(ctx, filepath, notify)
+			},
 		}
 		return src, nil
 	}
 }
 
+// loadLocalFiles loads the files in order, inferring the filetype of each from its extension.
+// The files they include are loaded before them, see `LocalFile`. A missing file matches `ErrNotFound`.
+func loadLocalFiles(ctx context.Context, k *koanf.Koanf, paths []string) error {
+	for _, path := range paths {
+		if err := loadFile(ctx, k, path, "", nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Env is a source that loads the config from environment variables.
 //
 // Only environment variables with the given prefix will be loaded.
 func Env[C ConfigModel](prefix string) SourceFunc[C] {
 	return func(mgr *Config[C]) (Source, error) {
-		kprovider := env.Provider(prefix, defaultDelimiter, func(s string) string {
-			// replace `__` with `.`, for example `PARENT__CHILD__NAME`
-			// will be merged into the config as nested "parent.child.name"
-			ret := strings.TrimPrefix(s, prefix)
-			ret = strings.ReplaceAll(strings.ToLower(ret), "__", defaultDelimiter)
-			return ret
-		})
-
 		src := Source{
-			Type: SourceTypeEnv,
+			Type:     SourceTypeEnv,
+			Location: prefix + "*",
 			Load: func(ctx context.Context, k *koanf.Koanf) error {
+				names := make(map[string]string)
+				kprovider := env.Provider(prefix, defaultDelimiter, func(s string) string {
+					// replace `__` with `.`, for example `PARENT__CHILD__NAME`
+					// will be merged into the config as nested "parent.child.name"
+					ret := strings.TrimPrefix(s, prefix)
+					ret = strings.ReplaceAll(strings.ToLower(ret), "__", defaultDelimiter)
+					names[ret] = s
+					return ret
+				})
+
 				err := k.Load(kprovider, nil)
 				if err != nil {
 					return fmt.Errorf("failed to load config from env vars: %w", err)
 				}
+
+				values := k.All()
+				for _, key := range sortedKeys(values) {
+					recordOrigin(ctx, names[key], map[string]interface{}{key: values[key]}, nil)
+				}
 				return nil
 			},
 		}
@@ -140,13 +200,25 @@ func PFlags[C ConfigModel](flagset *pflag.FlagSet) SourceFunc[C] {
 		}
 
 		src := Source{
-			Type: SourceTypePFlag,
+			Type:     SourceTypePFlag,
+			Location: "flags",
 			Load: func(ctx context.Context, k *koanf.Koanf) error {
-				kprovider := posflag.Provider(flagset, defaultDelimiter, k)
+				// Flags that were not changed only provide a value if no earlier source did.
+				merged := Merged(ctx)
+				if merged == nil {
+					merged = k
+				}
+
+				kprovider := posflag.Provider(flagset, defaultDelimiter, merged)
 				err := k.Load(kprovider, nil)
 				if err != nil {
 					return fmt.Errorf("failed to load config from posix flags: %w", err)
 				}
+
+				values := k.All()
+				for _, key := range sortedKeys(values) {
+					recordOrigin(ctx, "--"+key, map[string]interface{}{key: values[key]}, nil)
+				}
 				return nil
 			},
 		}
@@ -166,7 +238,8 @@ func Struct[C ConfigModel](s ConfigModel) SourceFunc[C] {
 		kprovider := structs.Provider(s, "koanf")
 
 		src := Source{
-			Type: SourceTypeStruct,
+			Type:     SourceTypeStruct,
+			Location: fmt.Sprintf("%T", s),
 			Load: func(ctx context.Context, k *koanf.Koanf) error {
 				if err := k.Load(kprovider, nil); err != nil {
 					return fmt.Errorf("failed to load config from struct: %w", err)
@@ -3,8 +3,12 @@ package ckoanf
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/knadh/koanf/v2"
 	"github.com/spf13/pflag"
@@ -175,3 +179,59 @@ func TestStruct(t *testing.T) {
 	_, err = Init(model, WithSource(Struct[*TestModel](nil)))
 	assert.Error(t, err)
 }
+
+func TestParallel(t *testing.T) {
+	const delay = 300 * time.Millisecond
+	slowServer := func(body string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+			}
+			_, _ = w.Write([]byte(body))
+		}))
+	}
+
+	var urls []string
+	for i := 0; i < 3; i++ {
+		server := slowServer(fmt.Sprintf("key = 'remote%d'\nabc = 'ab%d'", i, i))
+		defer server.Close()
+		urls = append(urls, server.URL+"/config.toml")
+	}
+
+	t.Setenv("PARALLEL_TEST__ABC", "env")
+	start := time.Now()
+	cfg, err := Init(&TestModel{}, WithSource(
+		EmbeddedDefaults[*TestModel]([]byte(tomlFixture), FileTypeTOML),
+		Parallel(Remote[*TestModel](urls[0], RemoteOptions{})),
+		Parallel(Remote[*TestModel](urls[1], RemoteOptions{})),
+		Env[*TestModel]("PARALLEL_TEST__"),
+		Parallel(Remote[*TestModel](urls[2], RemoteOptions{})),
+	))
+	assert.NoError(t, err)
+	assert.Less(t, time.Since(start), 2*delay)
+
+	// Merged in the order the sources were added
+	assert.Equal(t, "remote2", cfg.Model().Key)
+	assert.Equal(t, "ab2", cfg.Model().ABC)
+	exp, _ := cfg.Explain("abc")
+	locations := make([]string, len(exp.Origins))
+	for i, origin := range exp.Origins {
+		locations[i] = origin.Location
+	}
+	assert.Equal(t, []string{"embedded toml", urls[0], urls[1], "PARALLEL_TEST__ABC", urls[2]}, locations)
+
+	for _, src := range cfg.Report().Sources[1:] {
+		assert.Equal(t, SourceStatusLoaded, src.Status)
+	}
+
+	// A failing source stops the parallel sources that are still loading
+	start = time.Now()
+	_, err = Init(&TestModel{}, WithSource(
+		Parallel(Remote[*TestModel](urls[0], RemoteOptions{})),
+		EmbeddedDefaults[*TestModel]([]byte("invalid = "), FileTypeTOML),
+		Parallel(Remote[*TestModel](urls[1], RemoteOptions{})),
+	))
+	assert.ErrorIs(t, err, ErrParse)
+	assert.Less(t, time.Since(start), 2*delay)
+}
@@ -0,0 +1,176 @@
+package ckoanf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/knadh/koanf/v2"
+)
+
+// FieldError describes a single invalid config value.
+type FieldError struct {
+	// Key path of the value, for example `nested.foo`.
+	Key string
+	// Value as it is in the merged config, nil if no source set it.
+	Value interface{}
+	// Origin of the value (the last source that set it), nil if no source set it.
+	Origin *Origin
+
+	Err error
+}
+
+func (e FieldError) Error() string {
+	msg := e.Key + ": " + e.Err.Error()
+	if e.Origin == nil {
+		return msg
+	}
+
+	location := e.Origin.Type.String()
+	if e.Origin.Location != "" {
+		location += " " + e.Origin.Location
+	}
+	if e.Origin.Line > 0 {
+		location += fmt.Sprintf(":%d", e.Origin.Line)
+	}
+	return fmt.Sprintf("%s (value %v from %s)", msg, e.Value, location)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError is returned when the config model fails validation.
+type ValidationError struct {
+	// Fields holds one entry per invalid field, sorted by key path. It is only populated if the
+	// error returned by `Validate` can be attributed to fields, such as ozzo-validation's `validation.Errors`.
+	Fields []FieldError
+
+	// Err is the error returned by the model's `Validate` method.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return "failed to validate config model: " + e.Err.Error()
+	}
+
+	msgs := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		msgs[i] = field.Error()
+	}
+	return "failed to validate config model: " + strings.Join(msgs, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// newValidationError wraps the error returned by the model's `Validate` method,
+// attributing it to the values and origins of the invalid fields where possible.
+// The values of secrets are redacted.
+func (mgr *Config[C]) newValidationError(model C, k *koanf.Koanf, origins []Origin, err error) *ValidationError {
+	verr := &ValidationError{Err: err}
+
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		return verr
+	}
+
+	verr.Fields = FieldErrorsFromOzzo(model, errs)
+	for i := range verr.Fields {
+		field := &verr.Fields[i]
+		field.Value = k.Get(field.Key)
+		for j := len(origins) - 1; j >= 0; j-- {
+			if origins[j].Key == field.Key {
+				origin := origins[j]
+				field.Origin = &origin
+				break
+			}
+		}
+		if field.Value != nil && mgr.IsSecret(field.Key) {
+			field.Value = Redacted
+			if field.Origin != nil {
+				field.Origin.Value = Redacted
+			}
+		}
+	}
+	return verr
+}
+
+// FieldErrorsFromOzzo turns ozzo-validation errors for the given model into field errors keyed by
+// koanf key path. Ozzo names fields after their `json` tag (or Go name), these are mapped to the
+// `koanf` tag of the same field. Nested errors, such as those of nested structs, are flattened.
+func FieldErrorsFromOzzo(model interface{}, errs validation.Errors) []FieldError {
+	var fields []FieldError
+	ozzoFieldErrors(reflect.TypeOf(model), "", errs, &fields)
+
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Key < fields[j].Key
+	})
+	return fields
+}
+
+func ozzoFieldErrors(t reflect.Type, prefix string, errs validation.Errors, fields *[]FieldError) {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	for name, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		segment, fieldType := name, reflect.Type(nil)
+		if t != nil {
+			switch t.Kind() { //nolint:exhaustive // Other kinds have no nested fields.
+			case reflect.Struct:
+				if field, ok := findOzzoField(t, name); ok {
+					segment, fieldType = koanfFieldName(field), field.Type
+				}
+			case reflect.Map, reflect.Slice, reflect.Array:
+				fieldType = t.Elem()
+			}
+		}
+
+		path := prefix + segment
+		var nested validation.Errors
+		if errors.As(err, &nested) {
+			ozzoFieldErrors(fieldType, path+defaultDelimiter, nested, fields)
+			continue
+		}
+		*fields = append(*fields, FieldError{Key: path, Err: err})
+	}
+}
+
+// findOzzoField finds the struct field ozzo-validation reports errors under the given name for.
+func findOzzoField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if ozzoFieldName(field) == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// ozzoFieldName mirrors how ozzo-validation names fields in its errors.
+func ozzoFieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get(validation.ErrorTag); tag != "" && tag != "-" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// koanfFieldName returns the key a struct field is unmarshalled from.
+func koanfFieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("koanf"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
@@ -0,0 +1,96 @@
+package ckoanf
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationError(t *testing.T) {
+	t.Run("ozzo-validation errors", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("port: 8080\naddress:\n  country_code: INVALID\n"), 0o600))
+
+		_, err := Init(&MyExampleConfig{}, WithSource(LocalFile[*MyExampleConfig](path)))
+		require.Error(t, err)
+
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		require.Len(t, verr.Fields, 2)
+
+		countryCode := verr.Fields[0]
+		assert.Equal(t, "address.country_code", countryCode.Key)
+		assert.Equal(t, "INVALID", countryCode.Value)
+		require.NotNil(t, countryCode.Origin)
+		assert.Equal(t, SourceTypeLocalFile, countryCode.Origin.Type)
+		assert.Equal(t, path, countryCode.Origin.Location)
+		assert.Equal(t, 3, countryCode.Origin.Line)
+		assert.Contains(t, err.Error(), "address.country_code: the length must be exactly 2 (value INVALID from file "+path+":3)")
+
+		// Not set by any source
+		language := verr.Fields[1]
+		assert.Equal(t, "address.language", language.Key)
+		assert.Nil(t, language.Value)
+		assert.Nil(t, language.Origin)
+		assert.EqualError(t, language.Err, "cannot be blank")
+	})
+
+	t.Run("Other errors", func(t *testing.T) {
+		_, err := Init(&TestModel{}, WithSource(EmbeddedDefaults[*TestModel]([]byte("abc = 'x'"), FileTypeTOML)))
+		require.Error(t, err)
+
+		var verr *ValidationError
+		require.ErrorAs(t, err, &verr)
+		assert.Empty(t, verr.Fields)
+		assert.EqualError(t, verr, "failed to validate config model: Key is required")
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		cfg, err := New(&MyExampleConfig{Port: 1})
+		require.NoError(t, err)
+
+		var verr *ValidationError
+		require.ErrorAs(t, cfg.Validate(), &verr)
+		assert.Len(t, verr.Fields, 2)
+	})
+}
+
+type OzzoModel struct {
+	Name    string            `json:"display_name" koanf:"name"`
+	Servers []OzzoServer      `koanf:"servers"`
+	Labels  map[string]string `koanf:"labels"`
+}
+
+type OzzoServer struct {
+	Host string `koanf:"host"`
+}
+
+func (m *OzzoModel) Validate() error {
+	return nil
+}
+
+func TestFieldErrorsFromOzzo(t *testing.T) {
+	errRequired := errors.New("required")
+	errs := validation.Errors{
+		"display_name": errRequired,
+		"Servers": validation.Errors{
+			"1": validation.Errors{"Host": errRequired},
+		},
+		"Labels":  validation.Errors{"env": errRequired},
+		"Unknown": errRequired,
+		"Nil":     nil,
+	}
+
+	fields := FieldErrorsFromOzzo(&OzzoModel{}, errs)
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		keys[i] = field.Key
+		assert.ErrorIs(t, field, errRequired)
+	}
+	assert.Equal(t, []string{"Unknown", "labels.env", "name", "servers.1.host"}, keys)
+}
@@ -0,0 +1,152 @@
+package ckoanf
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const defaultReloadDebounce = 100 * time.Millisecond
+
+// Watch starts watching all sources that support it (such as `LocalFile`) for changes.
+// When a change is detected the whole source chain is reloaded, after waiting for the
+// debounce interval (see `WithReloadDebounce`) so that bursts of events only cause a single reload.
+//
+// The new config only replaces the current one if it unmarshals and passes validation, reload
+// errors are passed to the handler set with `WithReloadErrorHandler`.
+//
+// Watch does not block, watching stops when the given context is cancelled.
+func (mgr *Config[C]) Watch(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	changes := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changes <- struct{}{}:
+		default: // A reload is already pending.
+		}
+	}
+
+	for i, src := range mgr.sources {
+		if src.Watch == nil {
+			continue
+		}
+		if err := src.Watch(ctx, notify); err != nil {
+			cancel()
+			return fmt.Errorf("failed to watch provider %d (type=%s): %w", i, src.Type, err)
+		}
+	}
+
+	go func() {
+		defer cancel()
+		mgr.watchLoop(ctx, changes)
+	}()
+
+	return nil
+}
+
+// watchLoop debounces change notifications and reloads the config until the context is done.
+func (mgr *Config[C]) watchLoop(ctx context.Context, changes <-chan struct{}) {
+	timer := time.NewTimer(mgr.reloadDebounce)
+	if !timer.Stop() {
+		<-timer.C
+	}
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes:
+			timer.Reset(mgr.reloadDebounce)
+		case <-timer.C:
+			mgr.log(ctx, slog.LevelInfo, "reloading config after change")
+			if err := mgr.Load(ctx); err != nil && mgr.reloadErrorHandler != nil {
+				mgr.reloadErrorHandler(err)
+			}
+		}
+	}
+}
+
+// watchFile calls notify whenever the file at the given path changes.
+//
+// The parent directory is watched rather than the file itself, so that editors that save by
+// renaming a temporary file over the original are picked up. The path is also re-resolved on
+// every event, which catches symlink swaps such as the `..data` link in Kubernetes ConfigMap mounts.
+func watchFile(ctx context.Context, path string, notify func()) error {
+	path = filepath.Clean(path)
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := w.Add(dir); err != nil {
+		_ = w.Close()
+		return fmt.Errorf("failed to watch directory %s: %w", dir, err)
+	}
+
+	// The file may not exist yet, in which case it is picked up once it is created.
+	realPath, _ := filepath.EvalSymlinks(path)
+
+	// If the file is a symlink into another directory, writes to the target are only
+	// reported on that directory.
+	targetDir := ""
+	watchTarget := func() {
+		newTargetDir := ""
+		if realPath != "" && filepath.Dir(realPath) != dir {
+			newTargetDir = filepath.Dir(realPath)
+		}
+		if newTargetDir == targetDir {
+			return
+		}
+		if targetDir != "" {
+			_ = w.Remove(targetDir)
+		}
+		if newTargetDir != "" && w.Add(newTargetDir) != nil {
+			newTargetDir = ""
+		}
+		targetDir = newTargetDir
+	}
+	watchTarget()
+
+	go func() {
+		defer w.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				if event.Op == fsnotify.Chmod {
+					continue
+				}
+
+				name := filepath.Clean(event.Name)
+				curPath, _ := filepath.EvalSymlinks(path)
+				if name != path && name != realPath && curPath == realPath {
+					continue
+				}
+
+				realPath = curPath
+				watchTarget()
+				notify()
+			case _, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				// Events may have been dropped, reloading is always safe.
+				notify()
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package ckoanf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const watchTimeout = 5 * time.Second
+
+func initWatched(t *testing.T, path string, opts ...Option[*TestModel]) *Config[*TestModel] {
+	t.Helper()
+
+	opts = append([]Option[*TestModel]{
+		WithSource(LocalFile[*TestModel](path)),
+		WithReloadDebounce[*TestModel](10 * time.Millisecond),
+	}, opts...)
+
+	cfg, err := Init(&TestModel{}, opts...)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	require.NoError(t, cfg.Watch(ctx))
+
+	return cfg
+}
+
+func keyIsNew(cfg *Config[*TestModel]) func() bool {
+	return func() bool {
+		return cfg.Snapshot().Key == "new"
+	}
+}
+
+func TestWatch(t *testing.T) {
+	t.Run("Write in place", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(path, []byte("key = 'old'"), 0o600))
+
+		cfg := initWatched(t, path)
+		assert.Equal(t, "old", cfg.Model().Key)
+
+		require.NoError(t, os.WriteFile(path, []byte("key = 'new'"), 0o600))
+		assert.Eventually(t, keyIsNew(cfg), watchTimeout, 10*time.Millisecond)
+	})
+
+	t.Run("Save via rename", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "config.toml")
+		require.NoError(t, os.WriteFile(path, []byte("key = 'old'"), 0o600))
+
+		cfg := initWatched(t, path)
+
+		tmp := filepath.Join(dir, ".config.toml.swp")
+		require.NoError(t, os.WriteFile(tmp, []byte("key = 'new'"), 0o600))
+		require.NoError(t, os.Rename(tmp, path))
+		assert.Eventually(t, keyIsNew(cfg), watchTimeout, 10*time.Millisecond)
+	})
+
+	t.Run("ConfigMap symlink swap", func(t *testing.T) {
+		// Mimics the layout of a Kubernetes ConfigMap volume:
+		// config.toml -> ..data/config.toml, ..data -> ..v1
+		dir := t.TempDir()
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "..v1"), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "..v1", "config.toml"), []byte("key = 'old'"), 0o600))
+		require.NoError(t, os.Symlink("..v1", filepath.Join(dir, "..data")))
+		require.NoError(t, os.Symlink(filepath.Join("..data", "config.toml"), filepath.Join(dir, "config.toml")))
+
+		cfg := initWatched(t, filepath.Join(dir, "config.toml"))
+		assert.Equal(t, "old", cfg.Model().Key)
+
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "..v2"), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "..v2", "config.toml"), []byte("key = 'new'"), 0o600))
+		require.NoError(t, os.Symlink("..v2", filepath.Join(dir, "..data_tmp")))
+		require.NoError(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+		assert.Eventually(t, keyIsNew(cfg), watchTimeout, 10*time.Millisecond)
+	})
+
+	t.Run("Invalid config keeps previous model", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		require.NoError(t, os.WriteFile(path, []byte("key = 'old'"), 0o600))
+
+		errs := make(chan error, 10)
+		cfg := initWatched(t, path, WithReloadErrorHandler[*TestModel](func(err error) { errs <- err }))
+
+		// ABC must be of length 3
+		require.NoError(t, os.WriteFile(path, []byte("key = 'new'\nabc = 'abcd'"), 0o600))
+
+		select {
+		case err := <-errs:
+			assert.Error(t, err)
+		case <-time.After(watchTimeout):
+			t.Fatal("expected reload error")
+		}
+
+		assert.Equal(t, "old", cfg.Snapshot().Key)
+	})
+
+	t.Run("Invalid debounce", func(t *testing.T) {
+		_, err := New(&TestModel{}, WithReloadDebounce[*TestModel](-time.Second))
+		assert.Error(t, err)
+	})
+}